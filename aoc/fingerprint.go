@@ -0,0 +1,74 @@
+package aoc
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"os"
+)
+
+// normalizingHasher hashes input with CRLF line endings and trailing newlines removed, so the
+// same puzzle input saved on windows or without a final newline still has the same fingerprint.
+// newlines are held back until something other than a newline follows them
+type normalizingHasher struct {
+	h          hash.Hash
+	pendingCR  bool
+	pendingLFs int
+}
+
+func (n *normalizingHasher) flushNewlines() {
+	for ; n.pendingLFs > 0; n.pendingLFs-- {
+		n.h.Write([]byte{'\n'})
+	}
+}
+
+func (n *normalizingHasher) writeByte(b byte) {
+	if n.pendingCR {
+		n.pendingCR = false
+		if b != '\n' {
+			// a lone carriage return is content, not a line ending
+			n.flushNewlines()
+			n.h.Write([]byte{'\r'})
+		}
+	}
+
+	switch b {
+	case '\r':
+		n.pendingCR = true
+	case '\n':
+		n.pendingLFs++
+	default:
+		n.flushNewlines()
+		n.h.Write([]byte{b})
+	}
+}
+
+// Fingerprint returns a short hash identifying a puzzle input, ignoring line ending differences
+func Fingerprint(input io.Reader) (string, error) {
+	n := normalizingHasher{h: sha256.New()}
+	r := bufio.NewReader(input)
+	for {
+		b, err := r.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		n.writeByte(b)
+	}
+
+	return hex.EncodeToString(n.h.Sum(nil))[:16], nil
+}
+
+func FingerprintFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	return Fingerprint(file)
+}
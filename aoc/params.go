@@ -0,0 +1,162 @@
+package aoc
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Param is a named puzzle setting that differs between the example and the real input.
+// it is bound to a package level var in the day, much like the flag package does,
+// and whatever value the var holds at registration time becomes the default
+type Param struct {
+	Name    string
+	Usage   string
+	Default string
+
+	// Local params name files to read or write, or run commands that can, so they can only be
+	// set by whoever is running the days, never over http
+	Local bool
+
+	set func(string) error
+	get func() string
+}
+
+func (p *Param) Set(s string) error {
+	if err := p.set(s); err != nil {
+		return fmt.Errorf("param %s: %w", p.Name, err)
+	}
+	return nil
+}
+
+func (p *Param) String() string {
+	return p.get()
+}
+
+func (p *Param) Reset() {
+	p.set(p.Default)
+}
+
+func IntParam(name string, v *int, usage string) Param {
+	p := Param{Name: name, Usage: usage}
+	p.set = func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		*v = n
+		return nil
+	}
+	p.get = func() string { return strconv.Itoa(*v) }
+	p.Default = p.get()
+	return p
+}
+
+func StringParam(name string, v *string, usage string) Param {
+	p := Param{Name: name, Usage: usage}
+	p.set = func(s string) error {
+		*v = s
+		return nil
+	}
+	p.get = func() string { return *v }
+	p.Default = p.get()
+	return p
+}
+
+// Local marks a param as only settable by whoever is running the days, see Param.Local
+func Local(p Param) Param {
+	p.Local = true
+	return p
+}
+
+// AtLeast makes an IntParam or IntsParam refuse values below min, for settings that would
+// otherwise send a day off the end of a slice or into a loop that never finishes
+func AtLeast(min int, p Param) Param {
+	set := p.set
+	p.set = func(s string) error {
+		for _, field := range strings.Split(s, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil {
+				return err
+			}
+			if n < min {
+				return fmt.Errorf("%d is less than the minimum of %d", n, min)
+			}
+		}
+		return set(s)
+	}
+	return p
+}
+
+// IntsParam binds a comma separated list of ints
+func IntsParam(name string, v *[]int, usage string) Param {
+	p := Param{Name: name, Usage: usage}
+	p.set = func(s string) error {
+		ints := []int{}
+		for _, field := range strings.Split(s, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil {
+				return err
+			}
+			ints = append(ints, n)
+		}
+		*v = ints
+		return nil
+	}
+	p.get = func() string {
+		fields := make([]string, len(*v))
+		for i, n := range *v {
+			fields[i] = strconv.Itoa(n)
+		}
+		return strings.Join(fields, ",")
+	}
+	p.Default = p.get()
+	return p
+}
+
+func (d *Day) Param(name string) (*Param, bool) {
+	for i := range d.Params {
+		if d.Params[i].Name == name {
+			return &d.Params[i], true
+		}
+	}
+	return nil, false
+}
+
+// Overridden is whether any of the overrides changes a param from its default, in which case the
+// answers are for a different puzzle to the one the input was written for
+func (d *Day) Overridden(overrides map[string]string) bool {
+	for name, value := range overrides {
+		if p, ok := d.Param(name); !ok || p.Default != value {
+			return true
+		}
+	}
+	return false
+}
+
+// SetParams puts every param back to its default and then applies the overrides,
+// so settings from an earlier solve never leak into the next one
+func (d *Day) SetParams(overrides map[string]string) error {
+	for i := range d.Params {
+		d.Params[i].Reset()
+	}
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		p, ok := d.Param(name)
+		if !ok {
+			return fmt.Errorf("%s has no param %s", d, name)
+		}
+		if err := p.Set(overrides[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
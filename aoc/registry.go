@@ -0,0 +1,187 @@
+package aoc
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Solver runs one part of a puzzle against the puzzle input and returns the answer
+type Solver func(input io.Reader) any
+
+// Capability flags optional features a day supports beyond solving its parts
+type Capability int
+
+const (
+	Visualisable Capability = 1 << iota
+	Generator
+	CustomParams
+)
+
+var capabilityNames = []string{"visualisable", "generator", "params"}
+
+func (c Capability) String() string {
+	names := []string{}
+	for i, name := range capabilityNames {
+		if c&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// Mode is a tool a day offers alongside its parts, like an optimiser or a debugger.  modes aren't
+// part of the puzzle, so they have no answers to check or record, and running every day skips them
+type Mode struct {
+	Name  string
+	Usage string
+	Run   Solver
+}
+
+// Day describes a single puzzle, registered by each day's package at init time.
+// Input and Examples are relative to the year's directory, see YearDir
+type Day struct {
+	Year         int
+	Number       int
+	Title        string
+	Parts        []Solver
+	Modes        []Mode
+	Input        string
+	Examples     []string
+	Capabilities Capability
+	Params       []Param
+
+	// Validate checks input for assumptions the given part makes about its shape,
+	// beyond simply being parseable.  it is optional
+	Validate func(input io.Reader, part int) error
+
+	// Generate writes a puzzle input built from args.  it is optional, and sets the Generator capability
+	Generate func(w io.Writer, args []string) error
+}
+
+func (d *Day) String() string {
+	return fmt.Sprintf("%d day %d", d.Year, d.Number)
+}
+
+func (d *Day) Has(c Capability) bool {
+	return d.Capabilities&c == c
+}
+
+// Solve runs the given 1-indexed part against input
+func (d *Day) Solve(part int, input io.Reader) (any, error) {
+	if part < 1 || part > len(d.Parts) {
+		return nil, fmt.Errorf("%s has no part %d", d, part)
+	}
+
+	return d.Parts[part-1](input), nil
+}
+
+// Mode looks up one of the day's modes by name
+func (d *Day) Mode(name string) (*Mode, bool) {
+	for i := range d.Modes {
+		if d.Modes[i].Name == name {
+			return &d.Modes[i], true
+		}
+	}
+	return nil, false
+}
+
+// 2022 was written before there was more than one year, so its days live at the repo root
+var legacyYearDirs = map[int]string{
+	2022: ".",
+}
+
+// YearDir is where a year's days, inputs and answers live, relative to the repo root
+func YearDir(year int) string {
+	if dir, ok := legacyYearDirs[year]; ok {
+		return dir
+	}
+	return strconv.Itoa(year)
+}
+
+// Path resolves one of the day's input or example paths against the repo root
+func (d *Day) Path(root string, path string) string {
+	return filepath.Join(root, YearDir(d.Year), path)
+}
+
+// OpenInput opens the day's default input
+func (d *Day) OpenInput(root string) (*os.File, error) {
+	return os.Open(d.Path(root, d.Input))
+}
+
+type dayKey struct {
+	year   int
+	number int
+}
+
+var registry = map[dayKey]*Day{}
+
+// Register adds a day to the registry.  it is meant to be called from an init func,
+// so registering the same day twice is a programming error and panics
+func Register(d Day) {
+	if d.Year == 0 {
+		panic(fmt.Sprintf("day %d registered without a year", d.Number))
+	}
+	key := dayKey{d.Year, d.Number}
+	if _, ok := registry[key]; ok {
+		panic(fmt.Sprintf("%s registered twice", &d))
+	}
+	if len(d.Parts) == 0 {
+		panic(fmt.Sprintf("%s registered without any parts", &d))
+	}
+
+	if len(d.Params) > 0 {
+		d.Capabilities |= CustomParams
+	}
+	if d.Generate != nil {
+		d.Capabilities |= Generator
+	}
+
+	registry[key] = &d
+}
+
+func Lookup(year int, number int) (*Day, bool) {
+	d, ok := registry[dayKey{year, number}]
+	return d, ok
+}
+
+// Days returns every registered day, ordered by year and then day number
+func Days() []*Day {
+	days := make([]*Day, 0, len(registry))
+	for _, d := range registry {
+		days = append(days, d)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		if days[i].Year != days[j].Year {
+			return days[i].Year < days[j].Year
+		}
+		return days[i].Number < days[j].Number
+	})
+	return days
+}
+
+// YearDays returns the registered days of a single year, ordered by day number
+func YearDays(year int) []*Day {
+	days := []*Day{}
+	for _, d := range Days() {
+		if d.Year == year {
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+// Years returns every year with at least one registered day, in order
+func Years() []int {
+	years := []int{}
+	for _, d := range Days() {
+		if len(years) == 0 || years[len(years)-1] != d.Year {
+			years = append(years, d.Year)
+		}
+	}
+	return years
+}
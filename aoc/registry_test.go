@@ -0,0 +1,104 @@
+package aoc
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// answer is a part that always gives the same answer, whatever the input
+func answer(a any) Solver {
+	return func(io.Reader) any { return a }
+}
+
+func TestSolve(t *testing.T) {
+	d := Day{Year: 2022, Number: 1, Parts: []Solver{answer(1), answer("two")}}
+	tests := []struct {
+		part    int
+		want    any
+		wantErr bool
+	}{
+		{1, 1, false},
+		{2, "two", false},
+		{0, nil, true},
+		{3, nil, true},
+		{-1, nil, true},
+	}
+
+	for _, test := range tests {
+		got, err := d.Solve(test.part, strings.NewReader(""))
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("part %d: expected an error, got %v", test.part, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("part %d: %v", test.part, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("part %d: got %v, want %v", test.part, got, test.want)
+		}
+	}
+}
+
+// registers runs Register, turning a panic into an error
+func registers(d Day) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	Register(d)
+	return nil
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		d   Day
+		err string
+	}{
+		{Day{Year: 2022, Number: 3, Parts: []Solver{answer(3)}}, ""},
+		{Day{Year: 2023, Number: 1, Parts: []Solver{answer(1)}}, ""},
+		{Day{Year: 2022, Number: 1, Parts: []Solver{answer(1)}}, ""},
+		{Day{Year: 2022, Number: 2, Parts: []Solver{answer(2)}}, ""},
+		{Day{Year: 2022, Number: 2, Parts: []Solver{answer(2)}}, "2022 day 2 registered twice"},
+		{Day{Year: 2022, Number: 4}, "2022 day 4 registered without any parts"},
+		{Day{Number: 5, Parts: []Solver{answer(5)}}, "day 5 registered without a year"},
+	}
+
+	for _, test := range tests {
+		got := ""
+		if err := registers(test.d); err != nil {
+			got = err.Error()
+		}
+		if got != test.err {
+			t.Errorf("%s: got %q, want %q", &test.d, got, test.err)
+		}
+	}
+
+	days := []string{}
+	for _, d := range Days() {
+		days = append(days, d.String())
+	}
+	if want := "[2022 day 1 2022 day 2 2022 day 3 2023 day 1]"; fmt.Sprint(days) != want {
+		t.Errorf("got days %v, want %s", days, want)
+	}
+	if fmt.Sprint(Years()) != "[2022 2023]" {
+		t.Errorf("got years %v, want [2022 2023]", Years())
+	}
+	if len(YearDays(2023)) != 1 {
+		t.Errorf("got %d days for 2023, want 1", len(YearDays(2023)))
+	}
+	if _, ok := Lookup(2022, 2); !ok {
+		t.Error("2022 day 2 isn't registered")
+	}
+	if _, ok := Lookup(2023, 2); ok {
+		t.Error("2023 day 2 was never registered")
+	}
+	if _, ok := Lookup(2022, 4); ok {
+		t.Error("2022 day 4 was registered without any parts")
+	}
+}
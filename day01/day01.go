@@ -1,79 +1,43 @@
-package main
+package day01
 
 import (
-	"bufio"
-	"os"
-	"strconv"
-	"strings"
+	"io"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
-func part1(file *os.File) {
-	max := 0
-	current := 0
+var topCount = 3
+var distributionBuckets = 10
 
-	sc := bufio.NewScanner(file)
-	for sc.Scan() {
-		line := sc.Text()
-		if line == "" {
-			if current > max {
-				max = current
-			}
-			current = 0
-		} else {
-			calories, err := strconv.Atoi(line)
-			if err != nil {
-				panic(err)
-			}
-			current += calories
-		}
+func part1(file io.Reader) any {
+	report, err := Inventory(file, 1, 0)
+	if err != nil {
+		panic(err)
 	}
 
-	println(max)
+	return report.Total
 }
 
-func part2(file *os.File) {
-	maxes := []int{0, 0, 0}
-	current := 0
-
-	sc := bufio.NewScanner(file)
-	for sc.Scan() {
-		line := sc.Text()
-		calories, err := strconv.Atoi(line)
-		if err == nil {
-			current += calories
-			continue
-		}
-
-		if current > maxes[0] {
-			maxes[2] = maxes[1]
-			maxes[1] = maxes[0]
-			maxes[0] = current
-		} else if current > maxes[1] {
-			maxes[2] = maxes[1]
-			maxes[1] = current
-		} else if current > maxes[2] {
-			maxes[2] = current
-		}
-
-		current = 0
+func part2(file io.Reader) any {
+	report, err := Inventory(file, topCount, distributionBuckets)
+	if err != nil {
+		panic(err)
 	}
+	report.print()
 
-	println(maxes[0] + maxes[1] + maxes[2])
+	return report.Total
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
-	}
-
-	file, _ := os.Open(filename)
-	defer file.Close()
-	method(file)
+func init() {
+	aoc.Register(aoc.Day{
+		Year:   2022,
+		Number: 1,
+		Title:  "Calorie Counting",
+		Input:  "day01/input.txt",
+		Parts:  []aoc.Solver{part1, part2},
+		Params: []aoc.Param{
+			aoc.AtLeast(1, aoc.IntParam("top", &topCount, "how many of the best stocked elves part 2 totals up")),
+			aoc.AtLeast(0, aoc.IntParam("buckets", &distributionBuckets, "buckets in part 2's distribution of calories per elf, 0 leaves the statistics out")),
+		},
+	})
 }
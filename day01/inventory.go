@@ -0,0 +1,325 @@
+package day01
+
+import (
+	"bufio"
+	"container/heap"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Elf is one elf's share of the inventory.  Index counts from 1 in the order the elves appear
+type Elf struct {
+	Index    int
+	Items    int
+	Calories int
+}
+
+// ReadElves streams the inventory, calling fn with each elf as soon as its last item has been read.
+// blank lines separate elves, and the last elf counts whether or not the input ends with one
+func ReadElves(r io.Reader, fn func(Elf)) error {
+	current := Elf{Index: 1}
+	flush := func() {
+		if current.Items > 0 {
+			fn(current)
+			current = Elf{Index: current.Index + 1}
+		}
+	}
+
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			flush()
+			continue
+		}
+
+		calories, err := strconv.Atoi(text)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		current.Items++
+		current.Calories += calories
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+
+	flush()
+	return nil
+}
+
+// elfHeap is a min heap on calories, so the smallest of the current top elves is the one to drop.
+// ties go to the earlier elf, which keeps the results stable
+type elfHeap []Elf
+
+func (h elfHeap) Len() int { return len(h) }
+func (h elfHeap) Less(i, j int) bool {
+	if h[i].Calories != h[j].Calories {
+		return h[i].Calories < h[j].Calories
+	}
+	return h[i].Index > h[j].Index
+}
+func (h elfHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *elfHeap) Push(x any)   { *h = append(*h, x.(Elf)) }
+func (h *elfHeap) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
+
+// TopK keeps the k elves carrying the most calories seen so far, using O(k) memory
+type TopK struct {
+	k     int
+	elves elfHeap
+}
+
+func NewTopK(k int) *TopK {
+	return &TopK{k: k}
+}
+
+func (t *TopK) Add(e Elf) {
+	if t.k <= 0 {
+		return
+	}
+	if len(t.elves) < t.k {
+		heap.Push(&t.elves, e)
+		return
+	}
+	// the smallest of the top elves is at the root, so e only gets in by beating it
+	if (elfHeap{t.elves[0], e}).Less(0, 1) {
+		t.elves[0] = e
+		heap.Fix(&t.elves, 0)
+	}
+}
+
+// Elves returns the top elves, most calories first
+func (t *TopK) Elves() []Elf {
+	elves := append([]Elf{}, t.elves...)
+	sort.Slice(elves, func(i, j int) bool {
+		return elfHeap(elves).Less(j, i)
+	})
+	return elves
+}
+
+func (t *TopK) Total() int {
+	total := 0
+	for _, e := range t.elves {
+		total += e.Calories
+	}
+	return total
+}
+
+// Bucket counts the elves whose totals fall in [From, To)
+type Bucket struct {
+	From  int
+	To    int
+	Count int
+}
+
+type Stats struct {
+	Elves        int
+	Calories     int
+	Min          int
+	Max          int
+	Mean         float64
+	Median       float64
+	Distribution []Bucket
+}
+
+// histogramResolution is how many bins statsCollector keeps at most.  while the totals span
+// fewer calories than this each bin holds a single value and every statistic is exact
+const histogramResolution = 1024
+
+// statsCollector streams the elves into a histogram rather than keeping every total, so it needs
+// the same memory however many elves there are.  the bins start one calorie wide and double in
+// width, merging in pairs, whenever there would be more than histogramResolution of them.  count,
+// sum, min and max are kept exactly; the median and distribution come from the bins, so they're
+// only approximate once the bins are wider than a calorie
+type statsCollector struct {
+	count int
+	sum   int
+	min   int
+	max   int
+	width int
+	bins  map[int]int
+}
+
+func newStatsCollector() *statsCollector {
+	return &statsCollector{width: 1, bins: map[int]int{}}
+}
+
+func (s *statsCollector) Add(e Elf) {
+	if s.count == 0 || e.Calories < s.min {
+		s.min = e.Calories
+	}
+	if s.count == 0 || e.Calories > s.max {
+		s.max = e.Calories
+	}
+	s.count++
+	s.sum += e.Calories
+
+	s.bins[floorDiv(e.Calories, s.width)]++
+	for len(s.bins) > histogramResolution {
+		merged := make(map[int]int, len(s.bins)/2+1)
+		for bin, count := range s.bins {
+			merged[floorDiv(bin, 2)] += count
+		}
+		s.bins = merged
+		s.width *= 2
+	}
+}
+
+// floorDiv rounds towards negative infinity, so negative totals land in the right bin
+func floorDiv(a int, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// sortedBins lists the bins in order as (bin, count) pairs
+func (s *statsCollector) sortedBins() [][2]int {
+	bins := make([][2]int, 0, len(s.bins))
+	for bin, count := range s.bins {
+		bins = append(bins, [2]int{bin, count})
+	}
+	sort.Slice(bins, func(i, j int) bool { return bins[i][0] < bins[j][0] })
+	return bins
+}
+
+// valueAt estimates the total at the given rank, counting from 0, by spreading each bin's elves
+// evenly across it
+func (s *statsCollector) valueAt(bins [][2]int, rank int) int {
+	before := 0
+	for _, b := range bins {
+		if rank < before+b[1] {
+			v := b[0]*s.width + (rank-before)*s.width/b[1]
+			if v < s.min {
+				v = s.min
+			}
+			if v > s.max {
+				v = s.max
+			}
+			return v
+		}
+		before += b[1]
+	}
+	return s.max
+}
+
+// Stats summarises the elves added so far, spreading the distribution over the given number of buckets
+func (s *statsCollector) Stats(buckets int) Stats {
+	stats := Stats{Elves: s.count}
+	if s.count == 0 {
+		return stats
+	}
+
+	stats.Calories = s.sum
+	stats.Min = s.min
+	stats.Max = s.max
+	stats.Mean = float64(s.sum) / float64(s.count)
+
+	bins := s.sortedBins()
+	stats.Median = float64(s.valueAt(bins, (s.count-1)/2)+s.valueAt(bins, s.count/2)) / 2
+
+	if buckets < 1 {
+		buckets = 1
+	}
+	// round the width up so the largest total still lands in the last bucket
+	width := (stats.Max-stats.Min)/buckets + 1
+	for i := 0; i < buckets; i++ {
+		from := stats.Min + i*width
+		stats.Distribution = append(stats.Distribution, Bucket{From: from, To: from + width})
+	}
+	// each bin goes to the bucket its middle falls in
+	for _, b := range bins {
+		middle := b[0]*s.width + (s.width-1)/2
+		if middle < stats.Min {
+			middle = stats.Min
+		}
+		if middle > stats.Max {
+			middle = stats.Max
+		}
+		stats.Distribution[(middle-stats.Min)/width].Count += b[1]
+	}
+
+	return stats
+}
+
+// Report is what Inventory found.  Stats is nil unless they were asked for
+type Report struct {
+	Top   []Elf
+	Total int
+	Stats *Stats
+}
+
+// Inventory reads the whole inventory in one pass, reporting the top k elves, and statistics over
+// all of them spread over the given number of buckets.  buckets of 0 leaves the statistics out
+func Inventory(r io.Reader, k int, buckets int) (Report, error) {
+	top := NewTopK(k)
+	var stats *statsCollector
+	if buckets > 0 {
+		stats = newStatsCollector()
+	}
+	err := ReadElves(r, func(e Elf) {
+		top.Add(e)
+		if stats != nil {
+			stats.Add(e)
+		}
+	})
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{Top: top.Elves(), Total: top.Total()}
+	if stats != nil {
+		s := stats.Stats(buckets)
+		report.Stats = &s
+	}
+	return report, nil
+}
+
+// maxBarWidth is the longest bar in the distribution, which the rest are scaled against
+const maxBarWidth = 60
+
+// barLength scales count against the largest bucket, never rounding a non-empty bucket down to nothing
+func barLength(count int, most int) int {
+	if most <= maxBarWidth {
+		return count
+	}
+	n := count * maxBarWidth / most
+	if n == 0 && count > 0 {
+		n = 1
+	}
+	return n
+}
+
+func (r Report) print() {
+	fmt.Printf("Top %d elves:\n", len(r.Top))
+	for _, e := range r.Top {
+		fmt.Printf("  elf %d: %d calories in %d items\n", e.Index, e.Calories, e.Items)
+	}
+
+	s := r.Stats
+	if s == nil {
+		return
+	}
+	fmt.Printf("%d elves carrying %d calories, min %d, max %d, mean %.1f, median %.1f\n",
+		s.Elves, s.Calories, s.Min, s.Max, s.Mean, s.Median)
+	most := 0
+	for _, b := range s.Distribution {
+		if b.Count > most {
+			most = b.Count
+		}
+	}
+	for _, b := range s.Distribution {
+		fmt.Printf("  %6d-%-6d %4d %s\n", b.From, b.To-1, b.Count, strings.Repeat("#", barLength(b.Count, most)))
+	}
+}
@@ -1,31 +1,34 @@
-package main
+package day02
 
 import (
 	"bufio"
-	"os"
+	"fmt"
+	"io"
 	"strings"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
-func part1(file *os.File) {
-	shape_scores := map[string]int{"X": 1, "Y": 2, "Z": 3}
-	const SCORE_WIN = 6
-	const SCORE_DRAW = 3
-	const SCORE_LOSS = 0
-
-	outcomes := map[string]int{
-		"A X": SCORE_DRAW,
-		"A Y": SCORE_WIN,
-		"A Z": SCORE_LOSS,
-		"B X": SCORE_LOSS,
-		"B Y": SCORE_DRAW,
-		"B Z": SCORE_WIN,
-		"C X": SCORE_WIN,
-		"C Y": SCORE_LOSS,
-		"C Z": SCORE_DRAW,
+// rules_file loads a variant of the game, like rpsls.json, instead of the standard rules
+var rules_file = ""
+
+// strategy_constraint limits the optimal plan that the strategy mode finds, see parse_constraint
+var strategy_constraint = ""
+
+func current_rules() *Rules {
+	if rules_file == "" {
+		return &standard_rules
 	}
 
-	score := 0
+	rules, err := load_rules(rules_file)
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
 
+// read_guide calls fn with the two columns of every line in the strategy guide
+func read_guide(file io.Reader, fn func(opponent string, second string) error) {
 	sc := bufio.NewScanner(file)
 	for sc.Scan() {
 		line := sc.Text()
@@ -33,104 +36,88 @@ func part1(file *os.File) {
 			continue
 		}
 
-		my_shape := line[2:3]
-		score += shape_scores[my_shape] + outcomes[line]
+		columns := strings.Fields(line)
+		if len(columns) != 2 {
+			panic(fmt.Sprintf("expected two columns, got %q", line))
+		}
+		if err := fn(columns[0], columns[1]); err != nil {
+			panic(err)
+		}
 	}
-
-	println(score)
-
 }
 
-// start part 2
-
-type MatchScore int
-
-const (
-	WIN  MatchScore = 6
-	DRAW            = 3
-	LOSS            = 0
-)
-
-// opponent moves will come in as A, B, or C; remap to R, P, or S
-var opponent_play_to_shape = map[string]string{"A": "R", "B": "P", "C": "S"}
-var shape_to_score = map[string]int{"R": 1, "P": 2, "S": 3}
-
-const OUTCOME_LOSE = "X"
-const OUTCOME_DRAW = "Y"
-const OUTCOME_WIN = "Z"
-
-func determine_required_shape(opponent_shape string, desired_outcome string) string {
-	if desired_outcome == OUTCOME_DRAW {
-		return opponent_shape
-	}
-
-	if (desired_outcome == OUTCOME_WIN && opponent_shape == "R") ||
-		(desired_outcome == OUTCOME_LOSE && opponent_shape == "S") {
-		return "P"
-	}
-
-	if (desired_outcome == OUTCOME_WIN && opponent_shape == "P") ||
-		(desired_outcome == OUTCOME_LOSE && opponent_shape == "R") {
-		return "S"
+func (r *Rules) opponent_shape(letter string) (string, error) {
+	shape, ok := r.Opponent[letter]
+	if !ok {
+		return "", fmt.Errorf("unknown opponent play %s", letter)
 	}
-
-	return "R"
+	return shape, nil
 }
 
-func score_match(my_shape string, op_shape string) MatchScore {
-	if my_shape == op_shape {
-		return DRAW
-	}
-
-	if my_shape == "R" && op_shape == "S" {
-		return WIN
-	}
+func part1(file io.Reader) any {
+	rules := current_rules()
+	score := 0
 
-	if my_shape == "P" && op_shape == "R" {
-		return WIN
-	}
+	read_guide(file, func(opponent string, second string) error {
+		op_shape, err := rules.opponent_shape(opponent)
+		if err != nil {
+			return err
+		}
+		my_shape, ok := rules.Response[second]
+		if !ok {
+			return fmt.Errorf("unknown response %s", second)
+		}
 
-	if my_shape == "S" && op_shape == "P" {
-		return WIN
-	}
+		score += rules.shape_score(my_shape) + int(rules.score_match(my_shape, op_shape))
+		return nil
+	})
 
-	return LOSS
+	return score
 }
 
-func part2(file *os.File) {
+func part2(file io.Reader) any {
+	rules := current_rules()
 	score := 0
 
-	sc := bufio.NewScanner(file)
-	for sc.Scan() {
-		line := sc.Text()
-		if line == "" {
-			continue
+	read_guide(file, func(opponent string, second string) error {
+		op_shape, err := rules.opponent_shape(opponent)
+		if err != nil {
+			return err
+		}
+		desired_outcome, ok := rules.Desired[second]
+		if !ok {
+			return fmt.Errorf("unknown outcome %s", second)
 		}
 
-		op_shape := opponent_play_to_shape[line[0:1]]
-		desired_outcome := line[2:3]
-		my_shape := determine_required_shape(op_shape, desired_outcome)
-		match_score := score_match(my_shape, op_shape)
-		shape_score := shape_to_score[my_shape]
-		score += int(match_score) + shape_score
-	}
+		my_shape, err := rules.determine_required_shape(op_shape, desired_outcome)
+		if err != nil {
+			return err
+		}
+		score += rules.shape_score(my_shape) + int(rules.score_match(my_shape, op_shape))
+		return nil
+	})
 
-	println(score)
+	return score
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
+func init() {
+	if err := standard_rules.prepare(); err != nil {
+		panic(err)
 	}
 
-	file, _ := os.Open(filename)
-	defer file.Close()
-	method(file)
+	aoc.Register(aoc.Day{
+		Year:     2022,
+		Number:   2,
+		Title:    "Rock Paper Scissors",
+		Input:    "day02/input.txt",
+		Examples: []string{"day02/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+		Modes: []aoc.Mode{
+			{Name: "strategy", Usage: "find the best responses to the opponent's column", Run: strategy},
+		},
+		Params: []aoc.Param{
+			aoc.Local(aoc.StringParam("rules", &rules_file, "json file with a variant of the rules, like day02/rpsls.json")),
+			aoc.StringParam("constraint", &strategy_constraint, "limit on the strategy mode's plan, like win<=1000 or draw>=500"),
+		},
+	})
 }
@@ -0,0 +1,159 @@
+package day02
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+type MatchScore int
+
+type Outcome string
+
+const (
+	LOSS Outcome = "loss"
+	DRAW Outcome = "draw"
+	WIN  Outcome = "win"
+)
+
+type Shape struct {
+	Name  string `json:"name"`
+	Score int    `json:"score"`
+}
+
+// Rules describes a game as data.  Opponent maps the first column of the strategy guide to
+// shapes, and the second column is read through Response when it's taken to be a shape (part 1)
+// or through Desired when it's taken to be an outcome (part 2)
+type Rules struct {
+	Shapes   []Shape             `json:"shapes"`
+	Beats    map[string][]string `json:"beats"`
+	Outcomes map[Outcome]int     `json:"outcomes"`
+	Opponent map[string]string   `json:"opponent"`
+	Response map[string]string   `json:"response"`
+	Desired  map[string]Outcome  `json:"desired"`
+	shapes   map[string]Shape
+	beats    map[string]map[string]bool
+}
+
+var standard_rules = Rules{
+	Shapes: []Shape{{"R", 1}, {"P", 2}, {"S", 3}},
+	Beats: map[string][]string{
+		"R": {"S"},
+		"P": {"R"},
+		"S": {"P"},
+	},
+	Outcomes: map[Outcome]int{WIN: 6, DRAW: 3, LOSS: 0},
+	Opponent: map[string]string{"A": "R", "B": "P", "C": "S"},
+	Response: map[string]string{"X": "R", "Y": "P", "Z": "S"},
+	Desired:  map[string]Outcome{"X": LOSS, "Y": DRAW, "Z": WIN},
+}
+
+// load_rules reads rules from a json file in the same shape as Rules, see rpsls.json
+func load_rules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := &Rules{}
+	if err := json.Unmarshal(data, rules); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := rules.prepare(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// prepare indexes the tables and checks they describe a playable game: every shape that's
+// mentioned exists, and no two shapes beat each other
+func (r *Rules) prepare() error {
+	r.shapes = map[string]Shape{}
+	for _, s := range r.Shapes {
+		if _, ok := r.shapes[s.Name]; ok {
+			return fmt.Errorf("shape %s is listed twice", s.Name)
+		}
+		r.shapes[s.Name] = s
+	}
+
+	r.beats = map[string]map[string]bool{}
+	for winner, losers := range r.Beats {
+		if _, ok := r.shapes[winner]; !ok {
+			return fmt.Errorf("beats has unknown shape %s", winner)
+		}
+		r.beats[winner] = map[string]bool{}
+		for _, loser := range losers {
+			if _, ok := r.shapes[loser]; !ok {
+				return fmt.Errorf("%s beats unknown shape %s", winner, loser)
+			}
+			if loser == winner {
+				return fmt.Errorf("%s can't beat itself", winner)
+			}
+			r.beats[winner][loser] = true
+		}
+	}
+	for winner, losers := range r.beats {
+		for loser := range losers {
+			if r.beats[loser][winner] {
+				return fmt.Errorf("%s and %s both beat each other", winner, loser)
+			}
+		}
+	}
+
+	for _, outcome := range []Outcome{WIN, DRAW, LOSS} {
+		if _, ok := r.Outcomes[outcome]; !ok {
+			return fmt.Errorf("no score for a %s", outcome)
+		}
+	}
+	for column, mapping := range map[string]map[string]string{"opponent": r.Opponent, "response": r.Response} {
+		for letter, shape := range mapping {
+			if _, ok := r.shapes[shape]; !ok {
+				return fmt.Errorf("%s %s maps to unknown shape %s", column, letter, shape)
+			}
+		}
+	}
+	for letter, outcome := range r.Desired {
+		if _, ok := r.Outcomes[outcome]; !ok {
+			return fmt.Errorf("desired %s maps to unknown outcome %s", letter, outcome)
+		}
+	}
+	return nil
+}
+
+func (r *Rules) outcome(my_shape string, op_shape string) Outcome {
+	if r.beats[my_shape][op_shape] {
+		return WIN
+	}
+	if r.beats[op_shape][my_shape] {
+		return LOSS
+	}
+	return DRAW
+}
+
+func (r *Rules) score_match(my_shape string, op_shape string) MatchScore {
+	return MatchScore(r.Outcomes[r.outcome(my_shape, op_shape)])
+}
+
+func (r *Rules) shape_score(shape string) int {
+	return r.shapes[shape].Score
+}
+
+// determine_required_shape picks the shape that gets the desired outcome.  with more than three
+// shapes, several can do it, so the one with the best shape score wins, then the earliest listed
+func (r *Rules) determine_required_shape(op_shape string, desired_outcome Outcome) (string, error) {
+	candidates := []Shape{}
+	for _, s := range r.Shapes {
+		if r.outcome(s.Name, op_shape) == desired_outcome {
+			candidates = append(candidates, s)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no shape gets a %s against %s", desired_outcome, op_shape)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates[0].Name, nil
+}
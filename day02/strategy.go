@@ -0,0 +1,227 @@
+package day02
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Constraint limits how many rounds may end in Outcome, either to at most or at least Count
+type Constraint struct {
+	Outcome Outcome
+	AtMost  bool
+	Count   int
+}
+
+// parse_constraint reads constraints like "win<=100" or "draw>=50".  an empty string is no constraint
+func parse_constraint(s string) (*Constraint, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	for _, op := range []string{"<=", ">="} {
+		outcome, count, ok := strings.Cut(s, op)
+		if !ok {
+			continue
+		}
+
+		c := &Constraint{Outcome: Outcome(outcome), AtMost: op == "<="}
+		switch c.Outcome {
+		case WIN, DRAW, LOSS:
+		default:
+			return nil, fmt.Errorf("unknown outcome %q in constraint %q", outcome, s)
+		}
+		n, err := strconv.Atoi(count)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid count in constraint %q", s)
+		}
+		c.Count = n
+		return c, nil
+	}
+	return nil, fmt.Errorf("expected a constraint like win<=100 or draw>=50, got %q", s)
+}
+
+func (c *Constraint) String() string {
+	if c == nil {
+		return "no constraint"
+	}
+	if c.AtMost {
+		return fmt.Sprintf("at most %d rounds ending in a %s", c.Count, c.Outcome)
+	}
+	return fmt.Sprintf("at least %d rounds ending in a %s", c.Count, c.Outcome)
+}
+
+// maxPlanChoices caps optimal_plan's table, which holds 8 bytes per round per count, at 128 MiB
+const maxPlanChoices = 1 << 24
+
+type Breakdown struct {
+	Total        int
+	ShapePoints  int
+	OutcomeCount map[Outcome]int
+	OutcomePoint map[Outcome]int
+}
+
+type Plan struct {
+	Moves     []string
+	Breakdown Breakdown
+}
+
+// optimal_plan picks a shape for every round against the opponent's known shapes, maximising the
+// total score without breaking the constraint.  the dp state is how many rounds so far have ended
+// in the constrained outcome, capped at Count for an at least constraint since any more than that
+// makes no difference.  the dp table is rounds x Count, so a constraint that can't bind skips it,
+// and one that would need more than maxPlanChoices cells is refused
+func (r *Rules) optimal_plan(op_shapes []string, constraint *Constraint) (Plan, error) {
+	if constraint == nil ||
+		(constraint.AtMost && constraint.Count >= len(op_shapes)) ||
+		(!constraint.AtMost && constraint.Count == 0) {
+		return r.greedy_plan(op_shapes), nil
+	}
+	limit := constraint.Count
+	if limit > len(op_shapes) {
+		if !constraint.AtMost {
+			return Plan{}, fmt.Errorf("can't get %s in %d rounds", constraint, len(op_shapes))
+		}
+		limit = len(op_shapes)
+	}
+
+	if len(op_shapes)*(limit+1) > maxPlanChoices {
+		return Plan{}, fmt.Errorf("planning %d rounds with %s needs more than %d choices", len(op_shapes), constraint, maxPlanChoices)
+	}
+
+	const unreachable = -1 << 62
+	best := make([]int, limit+1)
+	for c := range best {
+		best[c] = unreachable
+	}
+	best[0] = 0
+
+	// choices[i][c] is the shape played in round i to reach count c after it, and where it came from
+	type choice struct {
+		shape int32
+		prev  int32
+	}
+	choices := make([][]choice, len(op_shapes))
+
+	for i, op_shape := range op_shapes {
+		next := make([]int, limit+1)
+		for c := range next {
+			next[c] = unreachable
+		}
+		choices[i] = make([]choice, limit+1)
+
+		for c, score := range best {
+			if score == unreachable {
+				continue
+			}
+			for s, shape := range r.Shapes {
+				nc := c
+				if r.outcome(shape.Name, op_shape) == constraint.Outcome {
+					nc++
+				}
+				if nc > limit {
+					if constraint.AtMost {
+						continue
+					}
+					nc = limit
+				}
+
+				total := score + shape.Score + int(r.score_match(shape.Name, op_shape))
+				if total > next[nc] {
+					next[nc] = total
+					choices[i][nc] = choice{int32(s), int32(c)}
+				}
+			}
+		}
+		best = next
+	}
+
+	end := -1
+	if constraint.AtMost {
+		for c, score := range best {
+			if score != unreachable && (end == -1 || score > best[end]) {
+				end = c
+			}
+		}
+	} else if best[limit] != unreachable {
+		end = limit
+	}
+	if end == -1 {
+		return Plan{}, fmt.Errorf("no plan gets %s", constraint)
+	}
+
+	plan := Plan{Moves: make([]string, len(op_shapes))}
+	for i, c := len(op_shapes)-1, end; i >= 0; i-- {
+		ch := choices[i][c]
+		plan.Moves[i] = r.Shapes[ch.shape].Name
+		c = int(ch.prev)
+	}
+	plan.Breakdown = r.breakdown(op_shapes, plan.Moves)
+	return plan, nil
+}
+
+// greedy_plan plays the best scoring shape in every round, which is optimal when nothing ties
+// the rounds together
+func (r *Rules) greedy_plan(op_shapes []string) Plan {
+	plan := Plan{Moves: make([]string, len(op_shapes))}
+	for i, op_shape := range op_shapes {
+		best := 0
+		for _, shape := range r.Shapes {
+			score := shape.Score + int(r.score_match(shape.Name, op_shape))
+			if plan.Moves[i] == "" || score > best {
+				best = score
+				plan.Moves[i] = shape.Name
+			}
+		}
+	}
+	plan.Breakdown = r.breakdown(op_shapes, plan.Moves)
+	return plan
+}
+
+// breakdown scores each round with score_match, the same way the guide itself is scored
+func (r *Rules) breakdown(op_shapes []string, my_shapes []string) Breakdown {
+	b := Breakdown{OutcomeCount: map[Outcome]int{}, OutcomePoint: map[Outcome]int{}}
+	for i, op_shape := range op_shapes {
+		outcome := r.outcome(my_shapes[i], op_shape)
+		match_score := int(r.score_match(my_shapes[i], op_shape))
+		b.OutcomeCount[outcome]++
+		b.OutcomePoint[outcome] += match_score
+		b.ShapePoints += r.shape_score(my_shapes[i])
+		b.Total += match_score + r.shape_score(my_shapes[i])
+	}
+	return b
+}
+
+func (p Plan) print(constraint *Constraint) {
+	fmt.Printf("Best plan with %s:\n", constraint)
+	fmt.Printf("  moves: %s\n", strings.Join(p.Moves, " "))
+	fmt.Printf("  shapes: %d points\n", p.Breakdown.ShapePoints)
+	for _, outcome := range []Outcome{WIN, DRAW, LOSS} {
+		fmt.Printf("  %s: %d rounds, %d points\n", outcome, p.Breakdown.OutcomeCount[outcome], p.Breakdown.OutcomePoint[outcome])
+	}
+}
+
+// strategy is the optimiser's entry point.  only the opponent's column of the guide is used
+func strategy(file io.Reader) any {
+	rules := current_rules()
+	constraint, err := parse_constraint(strategy_constraint)
+	if err != nil {
+		panic(err)
+	}
+
+	op_shapes := []string{}
+	read_guide(file, func(opponent string, second string) error {
+		op_shape, err := rules.opponent_shape(opponent)
+		op_shapes = append(op_shapes, op_shape)
+		return err
+	})
+
+	plan, err := rules.optimal_plan(op_shapes, constraint)
+	if err != nil {
+		panic(err)
+	}
+	plan.print(constraint)
+
+	return plan.Breakdown.Total
+}
@@ -0,0 +1,108 @@
+package day02
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestOptimalPlan(t *testing.T) {
+	tests := []struct {
+		op_shapes  string
+		constraint string
+		want       int
+		wantErr    bool
+	}{
+		{"R P S", "", 24, false},
+		{"R P S", "win<=0", 15, false},
+		{"R P S", "win<=1", 19, false},
+		{"R P S", "win<=3", 24, false},
+		{"R P S", "win>=3", 24, false},
+		{"R P S", "draw>=3", 15, false},
+		{"R P S", "loss>=0", 24, false},
+		{"R P S", "loss>=4", 0, true},
+		{"", "", 0, false},
+	}
+
+	for _, test := range tests {
+		constraint, err := parse_constraint(test.constraint)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plan, err := standard_rules.optimal_plan(strings.Fields(test.op_shapes), constraint)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q with %s: expected an error, got a plan scoring %d", test.op_shapes, constraint, plan.Breakdown.Total)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q with %s: %v", test.op_shapes, constraint, err)
+			continue
+		}
+		if plan.Breakdown.Total != test.want {
+			t.Errorf("%q with %s: got %d, want %d", test.op_shapes, constraint, plan.Breakdown.Total, test.want)
+		}
+	}
+}
+
+// bestByBruteForce tries every sequence of moves, returning the best total that keeps to the
+// constraint, or -1 if none does
+func bestByBruteForce(r *Rules, op_shapes []string, constraint *Constraint) int {
+	best := -1
+	moves := make([]string, len(op_shapes))
+	var try func(i int)
+	try = func(i int) {
+		if i == len(op_shapes) {
+			b := r.breakdown(op_shapes, moves)
+			count := b.OutcomeCount[constraint.Outcome]
+			if (constraint.AtMost && count > constraint.Count) || (!constraint.AtMost && count < constraint.Count) {
+				return
+			}
+			if b.Total > best {
+				best = b.Total
+			}
+			return
+		}
+		for _, shape := range r.Shapes {
+			moves[i] = shape.Name
+			try(i + 1)
+		}
+	}
+	try(0)
+	return best
+}
+
+func TestOptimalPlanMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	constraints := []string{"win<=0", "win<=2", "win>=4", "draw<=1", "draw>=3", "loss>=2", "loss<=0"}
+
+	for round := 0; round < 20; round++ {
+		op_shapes := make([]string, 6)
+		for i := range op_shapes {
+			op_shapes[i] = standard_rules.Shapes[rng.Intn(len(standard_rules.Shapes))].Name
+		}
+
+		for _, s := range constraints {
+			constraint, err := parse_constraint(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := bestByBruteForce(&standard_rules, op_shapes, constraint)
+			plan, err := standard_rules.optimal_plan(op_shapes, constraint)
+			if want == -1 {
+				if err == nil {
+					t.Errorf("%v with %s: expected an error, got a plan scoring %d", op_shapes, constraint, plan.Breakdown.Total)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("%v with %s: %v", op_shapes, constraint, err)
+				continue
+			}
+			if plan.Breakdown.Total != want {
+				t.Errorf("%v with %s: got %d, want %d", op_shapes, constraint, plan.Breakdown.Total, want)
+			}
+		}
+	}
+}
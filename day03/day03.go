@@ -1,22 +1,39 @@
-package main
+package day03
 
 import (
 	"bufio"
-	"os"
-	"strings"
+	"fmt"
+	"io"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
-func getPriority(item_type string) int {
-	ascii := int(item_type[0])
-	if ascii > 96 {
-		return ascii - 96
+var compartment_count = 2
+var group_size = 3
+var priority_spec = "a-z,A-Z"
+
+// an item only has to be in this many compartments or group members to count, 0 means all of them
+var compartment_min = 0
+var group_min = 0
+
+func getPriorities() Priorities {
+	priorities, err := ParsePriorities(priority_spec)
+	if err != nil {
+		panic(err)
 	}
+	return priorities
+}
 
-	return ascii - 64 + 26
+// shared is the items in at least min of the sets, or every set when min is 0
+func shared(sets []ItemSet, min int) ItemSet {
+	if min == 0 {
+		return CommonToAll(sets)
+	}
+	return InAtLeast(sets, min)
 }
 
-func part1(file *os.File) {
-	var rucksack [53]int
+func part1(file io.Reader) any {
+	priorities := getPriorities()
 	priority_sum := 0
 
 	sc := bufio.NewScanner(file)
@@ -26,36 +43,24 @@ func part1(file *os.File) {
 			continue
 		}
 
-		rucksack_size := len(line)
-		for i := 0; i < rucksack_size/2; i++ {
-			priority := getPriority(line[i : i+1])
-			rucksack[priority] = 1
+		compartments, err := Compartments(line, compartment_count)
+		if err != nil {
+			panic(err)
 		}
-
-		for i := rucksack_size / 2; i < rucksack_size; i++ {
-			priority := getPriority(line[i : i+1])
-			if rucksack[priority] == 1 {
-				priority_sum += priority
-				break
-			}
-		}
-
-		// reset rucksack for next line
-		for i := 0; i < len(rucksack); i++ {
-			rucksack[i] = 0
+		priority, err := priorities.Sum(shared(compartments, compartment_min))
+		if err != nil {
+			panic(err)
 		}
+		priority_sum += priority
 	}
 
-	println(priority_sum)
+	return priority_sum
 }
 
-func part2(file *os.File) {
-	// a slot in the array for each possible priority (a-z, A-Z)
-	// the array is one larger than necessary so that the index matches the priority
-	var rucksack [53]bool
-	var shared_rucksack [53]int
+func part2(file io.Reader) any {
+	priorities := getPriorities()
 	priority_sum := 0
-	elf_seq := -1
+	group := []ItemSet{}
 
 	sc := bufio.NewScanner(file)
 	for sc.Scan() {
@@ -63,56 +68,41 @@ func part2(file *os.File) {
 		if line == "" {
 			continue
 		}
-		elf_seq += 1
-
-		for i := 0; i < len(line); i++ {
-			priority := getPriority(line[i : i+1])
-			// check if this priority has been seen for this elf
-			if rucksack[priority] == false {
-				// if it hasn't been seen, record it for this elf, and also increment the shared rucksack
-				rucksack[priority] = true
-				shared_rucksack[priority] += 1
-
-				// if the shared rucksack has been incremented 3 times, then all
-				// 3 elves have this priority, so add it to the sum
-				if shared_rucksack[priority] == 3 {
-					priority_sum += priority
-					break
-				}
-			}
-		}
 
-		// reset rucksack for next line
-		for i := range rucksack {
-			rucksack[i] = false
+		group = append(group, NewItemSet(line))
+		if len(group) < group_size {
+			continue
 		}
 
-		// after the third elf in the group, reset shared rucksack
-		if elf_seq == 2 {
-			for i := range shared_rucksack {
-				shared_rucksack[i] = 0
-			}
-
-			elf_seq = -1
+		priority, err := priorities.Sum(shared(group, group_min))
+		if err != nil {
+			panic(err)
 		}
+		priority_sum += priority
+		group = group[:0]
 	}
 
-	println(priority_sum)
-}
-
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
+	if len(group) != 0 {
+		panic(fmt.Sprintf("%d rucksacks left over after the last group of %d", len(group), group_size))
 	}
 
-	file, _ := os.Open(filename)
-	defer file.Close()
-	method(file)
+	return priority_sum
+}
+
+func init() {
+	aoc.Register(aoc.Day{
+		Year:     2022,
+		Number:   3,
+		Title:    "Rucksack Reorganization",
+		Input:    "day03/input.txt",
+		Examples: []string{"day03/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+		Params: []aoc.Param{
+			aoc.AtLeast(1, aoc.IntParam("compartments", &compartment_count, "compartments each rucksack is split into for part 1")),
+			aoc.AtLeast(0, aoc.IntParam("compartment-min", &compartment_min, "compartments an item must be in to count for part 1, 0 for all of them")),
+			aoc.AtLeast(1, aoc.IntParam("group-size", &group_size, "elves in each group for part 2")),
+			aoc.AtLeast(0, aoc.IntParam("group-min", &group_min, "rucksacks in a group an item must be in to count for part 2, 0 for all of them")),
+			aoc.StringParam("priorities", &priority_spec, "items in priority order, as ranges like a-z or single items"),
+		},
+	})
 }
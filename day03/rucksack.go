@@ -0,0 +1,167 @@
+package day03
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// ItemSet is a bitset with a bit for every possible item, so finding common items is just an and
+type ItemSet [4]uint64
+
+func NewItemSet(items string) ItemSet {
+	var s ItemSet
+	for i := 0; i < len(items); i++ {
+		s.Add(items[i])
+	}
+	return s
+}
+
+func (s *ItemSet) Add(item byte) {
+	s[item/64] |= 1 << (item % 64)
+}
+
+func (s ItemSet) Has(item byte) bool {
+	return s[item/64]&(1<<(item%64)) != 0
+}
+
+func (s ItemSet) Intersect(other ItemSet) ItemSet {
+	for i := range s {
+		s[i] &= other[i]
+	}
+	return s
+}
+
+func (s ItemSet) Union(other ItemSet) ItemSet {
+	for i := range s {
+		s[i] |= other[i]
+	}
+	return s
+}
+
+func (s ItemSet) Len() int {
+	n := 0
+	for _, word := range s {
+		n += bits.OnesCount64(word)
+	}
+	return n
+}
+
+// Items lists the items in the set in byte order
+func (s ItemSet) Items() []byte {
+	items := []byte{}
+	for i, word := range s {
+		for word != 0 {
+			bit := bits.TrailingZeros64(word)
+			items = append(items, byte(i*64+bit))
+			word &= word - 1
+		}
+	}
+	return items
+}
+
+// CommonToAll is the items found in every one of the sets
+func CommonToAll(sets []ItemSet) ItemSet {
+	if len(sets) == 0 {
+		return ItemSet{}
+	}
+
+	common := sets[0]
+	for _, s := range sets[1:] {
+		common = common.Intersect(s)
+	}
+	return common
+}
+
+// InAtLeast is the items found in at least k of the sets.  it keeps a bitset for each count from
+// 1 to k, and each set promotes its items from one count to the next, so it never visits items one by one
+func InAtLeast(sets []ItemSet, k int) ItemSet {
+	if k <= 0 {
+		var all ItemSet
+		for i := range all {
+			all[i] = ^uint64(0)
+		}
+		return all
+	}
+	if k > len(sets) {
+		return ItemSet{}
+	}
+
+	// atLeast[c] holds the items seen in at least c+1 of the sets so far
+	atLeast := make([]ItemSet, k)
+	for _, s := range sets {
+		for c := k - 1; c > 0; c-- {
+			atLeast[c] = atLeast[c].Union(atLeast[c-1].Intersect(s))
+		}
+		atLeast[0] = atLeast[0].Union(s)
+	}
+	return atLeast[k-1]
+}
+
+// Compartments splits a rucksack into n equally sized compartments
+func Compartments(rucksack string, n int) ([]ItemSet, error) {
+	if n <= 0 || len(rucksack)%n != 0 {
+		return nil, fmt.Errorf("can't split %d items into %d equal compartments", len(rucksack), n)
+	}
+
+	size := len(rucksack) / n
+	compartments := make([]ItemSet, n)
+	for i := range compartments {
+		compartments[i] = NewItemSet(rucksack[i*size : (i+1)*size])
+	}
+	return compartments, nil
+}
+
+// Priorities maps every item to its priority.  items without one are 0
+type Priorities [256]int
+
+// ParsePriorities builds a table from a comma separated list of ranges like "a-z,A-Z" or single
+// items, numbering them from 1 in the order they're listed
+func ParsePriorities(spec string) (Priorities, error) {
+	var p Priorities
+	next := 1
+	assign := func(item byte) error {
+		if p[item] != 0 {
+			return fmt.Errorf("item %q is listed twice", item)
+		}
+		p[item] = next
+		next++
+		return nil
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		if len(part) == 3 && part[1] == '-' {
+			if part[0] > part[2] {
+				return p, fmt.Errorf("range %q runs backwards", part)
+			}
+			for item := int(part[0]); item <= int(part[2]); item++ {
+				if err := assign(byte(item)); err != nil {
+					return p, err
+				}
+			}
+			continue
+		}
+
+		if part == "" {
+			return p, fmt.Errorf("empty item in %q", spec)
+		}
+		for i := 0; i < len(part); i++ {
+			if err := assign(part[i]); err != nil {
+				return p, err
+			}
+		}
+	}
+	return p, nil
+}
+
+// Sum adds up the priorities of every item in the set, which must all have one
+func (p *Priorities) Sum(s ItemSet) (int, error) {
+	sum := 0
+	for _, item := range s.Items() {
+		if p[item] == 0 {
+			return 0, fmt.Errorf("item %q has no priority", item)
+		}
+		sum += p[item]
+	}
+	return sum, nil
+}
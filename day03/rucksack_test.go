@@ -0,0 +1,69 @@
+package day03
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestInAtLeast(t *testing.T) {
+	tests := []struct {
+		sets string
+		k    int
+		want string
+	}{
+		{"vJrwpWtwJgWr hcsFMMfFFhFp", 2, "p"},
+		{"vJrwpWtwJgWrhcsFMMfFFhFp jqHRNqRjqzjGDLGLrsFMfFZSrLrFZsSL PmmdzqPrVvPwwTWBwg", 3, "r"},
+		{"abc bcd cde", 1, "abcde"},
+		{"abc bcd cde", 2, "bcd"},
+		{"abc bcd cde", 3, "c"},
+		{"abc bcd cde", 4, ""},
+		{"aaa a b", 2, "a"},
+		{"", 1, ""},
+		{"xyz ~!", 1, "!xyz~"},
+	}
+
+	for _, test := range tests {
+		sets := []ItemSet{}
+		for _, items := range strings.Fields(test.sets) {
+			sets = append(sets, NewItemSet(items))
+		}
+		if got := string(InAtLeast(sets, test.k).Items()); got != test.want {
+			t.Errorf("%q in at least %d: got %q, want %q", test.sets, test.k, got, test.want)
+		}
+	}
+
+	if got := InAtLeast(nil, 0).Len(); got != 256 {
+		t.Errorf("in at least 0 of nothing: got %d items, want every one of 256", got)
+	}
+}
+
+func TestInAtLeastMatchesCounts(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for round := 0; round < 50; round++ {
+		sets := make([]ItemSet, 1+rng.Intn(6))
+		counts := [256]int{}
+		for i := range sets {
+			for n := rng.Intn(40); n > 0; n-- {
+				// reach past the first word so every word of the bitset is exercised
+				item := byte(rng.Intn(256))
+				if !sets[i].Has(item) {
+					sets[i].Add(item)
+					counts[item]++
+				}
+			}
+		}
+
+		for k := 1; k <= len(sets)+1; k++ {
+			got := InAtLeast(sets, k)
+			for item, count := range counts {
+				if got.Has(byte(item)) != (count >= k) {
+					t.Fatalf("round %d: item %d is in %d sets, but InAtLeast(%d) has it %t", round, item, count, k, got.Has(byte(item)))
+				}
+			}
+		}
+		if InAtLeast(sets, len(sets)) != CommonToAll(sets) {
+			t.Fatalf("round %d: in all %d sets doesn't match CommonToAll", round, len(sets))
+		}
+	}
+}
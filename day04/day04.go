@@ -1,34 +1,31 @@
-package main
+package day04
 
 import (
 	"bufio"
-	"os"
+	"fmt"
+	"io"
 	"strconv"
 	"strings"
-)
 
-type assignment struct {
-	start int
-	end   int
-}
+	"citro.net/advent-2022-go/aoc"
+	"citro.net/advent-2022-go/lib/intervals"
+)
 
-func assignment_contains(a assignment, b assignment) bool {
-	return a.start <= b.start && a.end >= b.end
-}
+// an assignment is the closed range of sections an elf has to clean
+type assignment = intervals.Interval
 
-func assignment_overlaps(a assignment, b assignment) bool {
-	return (a.start >= b.start && a.start <= b.end) || (a.end >= b.start && a.end <= b.end)
-}
+// report_section, when set, makes the report list the elves assigned to that section
+var report_section = 0
 
 func parse_assignment(s string) assignment {
 	parts := strings.Split(s, "-")
 	start, _ := strconv.Atoi(parts[0])
 	end, _ := strconv.Atoi(parts[1])
-	return assignment{start, end}
+	return assignment{Start: start, End: end}
 }
 
-func part1(file *os.File) {
-	overlapping := 0
+// read_pairs calls fn with the two assignments on each line
+func read_pairs(file io.Reader, fn func(assignment1 assignment, assignment2 assignment)) {
 	sc := bufio.NewScanner(file)
 	for sc.Scan() {
 		line := sc.Text()
@@ -37,49 +34,98 @@ func part1(file *os.File) {
 		}
 
 		assignments := strings.Split(line, ",")
-		assignment1 := parse_assignment(assignments[0])
-		assignment2 := parse_assignment(assignments[1])
-		if assignment_contains(assignment1, assignment2) || assignment_contains(assignment2, assignment1) {
-			overlapping++
-		}
+		fn(parse_assignment(assignments[0]), parse_assignment(assignments[1]))
 	}
-
-	println(overlapping)
 }
 
-func part2(file *os.File) {
+func part1(file io.Reader) any {
 	overlapping := 0
-	sc := bufio.NewScanner(file)
-	for sc.Scan() {
-		line := sc.Text()
-		if line == "" {
-			continue
+	read_pairs(file, func(assignment1 assignment, assignment2 assignment) {
+		if assignment1.Contains(assignment2) || assignment2.Contains(assignment1) {
+			overlapping++
 		}
+	})
 
-		assignments := strings.Split(line, ",")
-		assignment1 := parse_assignment(assignments[0])
-		assignment2 := parse_assignment(assignments[1])
-		if assignment_overlaps(assignment1, assignment2) || assignment_overlaps(assignment2, assignment1) {
+	return overlapping
+}
+
+func part2(file io.Reader) any {
+	overlapping := 0
+	read_pairs(file, func(assignment1 assignment, assignment2 assignment) {
+		if assignment1.Overlaps(assignment2) {
 			overlapping++
 		}
-	}
+	})
 
-	println(overlapping)
+	return overlapping
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
+// report looks at every assignment in the input at once rather than pair by pair, listing the
+// sections nobody was assigned and the ones assigned to more than one elf.  it returns how many
+// sections were assigned more than once
+func report(file io.Reader) any {
+	all := []assignment{}
+	labels := []string{}
+	pair := 0
+	read_pairs(file, func(assignment1 assignment, assignment2 assignment) {
+		pair++
+		all = append(all, assignment1, assignment2)
+		labels = append(labels, fmt.Sprintf("pair %d elf 1", pair), fmt.Sprintf("pair %d elf 2", pair))
+	})
+
+	covered := intervals.Set{}
+	for _, a := range all {
+		covered.Insert(a)
+	}
+	spans := covered.Intervals()
+	if len(spans) == 0 {
+		return 0
+	}
+	camp := assignment{Start: spans[0].Start, End: spans[len(spans)-1].End}
+	fmt.Printf("%d assignments cover %d of sections %s\n", len(all), covered.Len(), camp)
+
+	unassigned := []string{}
+	for _, gap := range covered.Gaps(camp) {
+		unassigned = append(unassigned, gap.String())
+	}
+	if len(unassigned) == 0 {
+		unassigned = append(unassigned, "none")
+	}
+	fmt.Printf("Sections nobody was assigned: %s\n", strings.Join(unassigned, ", "))
+
+	shared := 0
+	fmt.Println("Sections assigned to more than one elf:")
+	for _, segment := range intervals.Coverage(all) {
+		if segment.Depth > 1 {
+			fmt.Printf("  %s: %d elves\n", segment.Interval, segment.Depth)
+			shared += segment.Len()
 		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
+	}
+
+	if report_section != 0 {
+		elves := []string{}
+		for _, id := range intervals.NewIndex(all).Stab(report_section) {
+			elves = append(elves, fmt.Sprintf("%s (%s)", labels[id], all[id]))
 		}
+		fmt.Printf("Section %d is assigned to %d elves: %s\n", report_section, len(elves), strings.Join(elves, ", "))
 	}
 
-	file, _ := os.Open(filename)
-	defer file.Close()
-	method(file)
+	return shared
+}
+
+func init() {
+	aoc.Register(aoc.Day{
+		Year:     2022,
+		Number:   4,
+		Title:    "Camp Cleanup",
+		Input:    "day04/input.txt",
+		Examples: []string{"day04/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+		Modes: []aoc.Mode{
+			{Name: "coverage", Usage: "report on the whole camp's assignments", Run: report},
+		},
+		Params: []aoc.Param{
+			aoc.IntParam("section", &report_section, "section that the coverage mode lists the assigned elves for"),
+		},
+	})
 }
@@ -0,0 +1,101 @@
+package day05
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// board holds the stacks in the order they're drawn, with the top crate of each stack first
+type board struct {
+	labels []string
+	stacks [][]byte
+}
+
+// a crate is drawn as [X], and each stack's column is one crate plus a space wide
+const column_width = 4
+
+// isLabelRow spots the row of stack labels under the drawing, which is all numbers
+func isLabelRow(line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false
+	}
+	for _, f := range fields {
+		if strings.Trim(f, "0123456789") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseBoard reads the drawing of the stacks, given its rows from top to bottom and then the label row.
+// there can be any number of stacks, and the labels are whatever the label row says
+func parseBoard(rows []string, label_row string) (board, error) {
+	b := board{labels: strings.Fields(label_row)}
+	b.stacks = make([][]byte, len(b.labels))
+
+	for _, row := range rows {
+		for i := 0; i < len(row); i++ {
+			if row[i] != '[' {
+				continue
+			}
+			if i+2 >= len(row) || row[i+2] != ']' || i%column_width != 0 {
+				return b, fmt.Errorf("badly drawn crate at column %d of %q", i+1, row)
+			}
+
+			stack := i / column_width
+			if stack >= len(b.stacks) {
+				return b, fmt.Errorf("crate at column %d of %q has no stack label under it", i+1, row)
+			}
+			b.stacks[stack] = append(b.stacks[stack], row[i+1])
+		}
+	}
+
+	return b, nil
+}
+
+func (b *board) index(label string) (int, error) {
+	for i, l := range b.labels {
+		if l == label {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("no stack labelled %s", label)
+}
+
+// render draws the board the same way the puzzle does, so it can be diffed against the illustrations
+func (b *board) render(w io.Writer) {
+	height := 0
+	for _, stack := range b.stacks {
+		if len(stack) > height {
+			height = len(stack)
+		}
+	}
+
+	cells := make([]string, len(b.stacks))
+	for level := height; level > 0; level-- {
+		for i, stack := range b.stacks {
+			cells[i] = "   "
+			if len(stack) >= level {
+				cells[i] = "[" + string(stack[len(stack)-level]) + "]"
+			}
+		}
+		fmt.Fprintln(w, strings.Join(cells, " "))
+	}
+
+	for i, label := range b.labels {
+		// labels sit under the crate letter, so longer ones run to the right
+		cells[i] = fmt.Sprintf(" %-2s", label)
+		if len(label) > 2 {
+			cells[i] = label
+		}
+	}
+	fmt.Fprintln(w, strings.Join(cells, " "))
+}
+
+func (b *board) String() string {
+	sb := strings.Builder{}
+	b.render(&sb)
+	return sb.String()
+}
@@ -0,0 +1,173 @@
+package day05
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// crane decides how the crates taken off the top of one stack end up on the next.  crates are
+// given top first, as they sat on the source, and returned top first, as they'll sit on the destination
+type crane interface {
+	lift(crates []byte) []byte
+	String() string
+}
+
+// crateMover9000 moves crates one at a time, which is the same as moving them all at once in reverse order
+type crateMover9000 struct{}
+
+func (crateMover9000) lift(crates []byte) []byte {
+	moved := make([]byte, len(crates))
+	for i, c := range crates {
+		moved[len(crates)-i-1] = c
+	}
+	return moved
+}
+
+func (crateMover9000) String() string { return "CrateMover 9000" }
+
+// crateMover9001 moves every crate at once, so they keep their order
+type crateMover9001 struct{}
+
+func (crateMover9001) lift(crates []byte) []byte {
+	return append([]byte{}, crates...)
+}
+
+func (crateMover9001) String() string { return "CrateMover 9001" }
+
+// maxLiftCrane takes up to max crates at a time off the top of the stack.  each load keeps its
+// order, but later loads land on top of earlier ones
+type maxLiftCrane struct {
+	max int
+}
+
+func (c maxLiftCrane) lift(crates []byte) []byte {
+	moved := make([]byte, 0, len(crates))
+	for start := 0; start < len(crates); start += c.max {
+		end := start + c.max
+		if end > len(crates) {
+			end = len(crates)
+		}
+		moved = append(append([]byte{}, crates[start:end]...), moved...)
+	}
+	return moved
+}
+
+func (c maxLiftCrane) String() string { return fmt.Sprintf("crane lifting %d at a time", c.max) }
+
+// parseCrane reads a crane model, 9000, 9001, or max:N for a crane that lifts N crates at a time
+func parseCrane(model string) (crane, error) {
+	switch model {
+	case "9000":
+		return crateMover9000{}, nil
+	case "9001":
+		return crateMover9001{}, nil
+	}
+
+	if n, ok := strings.CutPrefix(model, "max:"); ok {
+		max, err := strconv.Atoi(n)
+		if err != nil || max < 1 {
+			return nil, fmt.Errorf("invalid lift limit in crane %q", model)
+		}
+		return maxLiftCrane{max}, nil
+	}
+	return nil, fmt.Errorf("unknown crane %q, expected 9000, 9001 or max:N", model)
+}
+
+// journalEntry remembers the crates a move took, in their original order, so it can be undone
+type journalEntry struct {
+	move   move
+	crates []byte
+}
+
+// journal applies moves to a board with a crane, keeping enough history to step back and forth
+type journal struct {
+	board  *board
+	crane  crane
+	done   []journalEntry
+	undone []move
+}
+
+func newJournal(b *board, c crane) *journal {
+	return &journal{board: b, crane: c}
+}
+
+func (j *journal) describe(m move) string {
+	return fmt.Sprintf("move %d from %s to %s", m.qty, j.board.labels[m.source], j.board.labels[m.dest])
+}
+
+// apply makes a move, checking it first so the board is left alone if the move is impossible.
+// any moves that had been undone are forgotten
+func (j *journal) apply(m move) error {
+	if err := j.do(m); err != nil {
+		return err
+	}
+	j.undone = j.undone[:0]
+	return nil
+}
+
+func (j *journal) do(m move) error {
+	source := j.board.stacks[m.source]
+	if m.qty < 0 {
+		return fmt.Errorf("%s: can't move a negative number of crates", j.describe(m))
+	}
+	if m.qty > len(source) {
+		return fmt.Errorf("%s: stack %s only holds %d crates", j.describe(m), j.board.labels[m.source], len(source))
+	}
+
+	crates := append([]byte{}, source[:m.qty]...)
+	j.board.stacks[m.source] = source[m.qty:]
+	j.board.stacks[m.dest] = append(j.crane.lift(crates), j.board.stacks[m.dest]...)
+	j.done = append(j.done, journalEntry{m, crates})
+	return nil
+}
+
+// undo takes back the last move, returning false if there's nothing to undo
+func (j *journal) undo() bool {
+	if len(j.done) == 0 {
+		return false
+	}
+
+	e := j.done[len(j.done)-1]
+	j.done = j.done[:len(j.done)-1]
+	j.board.stacks[e.move.dest] = j.board.stacks[e.move.dest][e.move.qty:]
+	j.board.stacks[e.move.source] = append(append([]byte{}, e.crates...), j.board.stacks[e.move.source]...)
+	j.undone = append(j.undone, e.move)
+	return true
+}
+
+// redo replays the last undone move, returning false if there's nothing to redo
+func (j *journal) redo() (bool, error) {
+	if len(j.undone) == 0 {
+		return false, nil
+	}
+
+	m := j.undone[len(j.undone)-1]
+	if err := j.do(m); err != nil {
+		return false, err
+	}
+	j.undone = j.undone[:len(j.undone)-1]
+	return true, nil
+}
+
+// step is how many moves have been applied
+func (j *journal) step() int {
+	return len(j.done)
+}
+
+// seek undoes or redoes moves until the board is as it was after the given number of moves
+func (j *journal) seek(step int) error {
+	if step < 0 || step > len(j.done)+len(j.undone) {
+		return fmt.Errorf("no step %d, the journal has %d moves", step, len(j.done)+len(j.undone))
+	}
+
+	for j.step() > step {
+		j.undo()
+	}
+	for j.step() < step {
+		if _, err := j.redo(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package day05
+
+import (
+	"strings"
+	"testing"
+)
+
+const introDrawing = `    [D]
+[N] [C]
+[Z] [M] [P]
+ 1   2   3
+
+move 1 from 2 to 1
+move 3 from 1 to 3
+move 2 from 2 to 1
+move 1 from 1 to 2
+`
+
+func TestCranes(t *testing.T) {
+	tests := []struct {
+		model  string
+		crates string
+		want   string
+	}{
+		{"9000", "ABCD", "DCBA"},
+		{"9001", "ABCD", "ABCD"},
+		{"max:1", "ABCD", "DCBA"},
+		{"max:2", "ABCDE", "ECDAB"},
+		{"max:3", "ABCD", "DABC"},
+		{"max:4", "ABCD", "ABCD"},
+		{"max:9", "ABCD", "ABCD"},
+		{"9001", "", ""},
+	}
+
+	for _, test := range tests {
+		c, err := parseCrane(test.model)
+		if err != nil {
+			t.Fatal(err)
+		}
+		crates := []byte(test.crates)
+		if got := string(c.lift(crates)); got != test.want {
+			t.Errorf("%s lifting %s: got %s, want %s", c, test.crates, got, test.want)
+		}
+		if string(crates) != test.crates {
+			t.Errorf("%s changed the crates it was given to %s", c, crates)
+		}
+	}
+
+	for _, model := range []string{"", "9002", "max:", "max:0", "max:-1", "max:x"} {
+		if _, err := parseCrane(model); err == nil {
+			t.Errorf("%q: expected an error", model)
+		}
+	}
+}
+
+// stacks lists each stack top first, to compare boards by
+func stacks(b *board) string {
+	s := []string{}
+	for _, stack := range b.stacks {
+		s = append(s, string(stack))
+	}
+	return strings.Join(s, " ")
+}
+
+func TestJournal(t *testing.T) {
+	// the board after each of the intro's moves with a CrateMover 9000
+	after := []string{"NZ DCM P", "DNZ CM P", " CM ZNDP", "MC  ZNDP", "C M ZNDP"}
+
+	g, err := readGame(strings.NewReader(introDrawing))
+	if err != nil {
+		t.Fatal(err)
+	}
+	j := newJournal(&g.board, crateMover9000{})
+	for i, m := range g.moves {
+		if err := j.apply(m); err != nil {
+			t.Fatal(err)
+		}
+		if got := stacks(&g.board); got != after[i+1] {
+			t.Fatalf("after move %d: got %q, want %q", i+1, got, after[i+1])
+		}
+	}
+
+	for _, step := range []int{2, 0, 4, 1, 1, 3} {
+		if err := j.seek(step); err != nil {
+			t.Fatal(err)
+		}
+		if j.step() != step {
+			t.Errorf("seek(%d) left the journal at step %d", step, j.step())
+		}
+		if got := stacks(&g.board); got != after[step] {
+			t.Errorf("after seek(%d): got %q, want %q", step, got, after[step])
+		}
+	}
+	for _, step := range []int{-1, 5} {
+		if err := j.seek(step); err == nil {
+			t.Errorf("seek(%d): expected an error", step)
+		}
+	}
+
+	// a fresh move forgets the ones that were undone
+	if err := j.seek(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.apply(move{qty: 1, source: 2, dest: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if got := stacks(&g.board); got != "DNZ PCM " {
+		t.Errorf("after a new move: got %q, want %q", got, "DNZ PCM ")
+	}
+	if ok, err := j.redo(); ok || err != nil {
+		t.Errorf("redo after a new move: got %t, %v, want nothing to redo", ok, err)
+	}
+
+	for j.undo() {
+	}
+	if got := stacks(&g.board); got != after[0] {
+		t.Errorf("after undoing everything: got %q, want %q", got, after[0])
+	}
+}
+
+func TestJournalRefusesImpossibleMoves(t *testing.T) {
+	g, err := readGame(strings.NewReader(introDrawing))
+	if err != nil {
+		t.Fatal(err)
+	}
+	j := newJournal(&g.board, crateMover9001{})
+
+	for _, m := range []move{{qty: 2, source: 2, dest: 0}, {qty: -1, source: 0, dest: 1}} {
+		if err := j.apply(m); err == nil {
+			t.Errorf("%s: expected an error", j.describe(m))
+		}
+		if got := stacks(&g.board); got != "NZ DCM P" {
+			t.Errorf("%s: the board changed to %q", j.describe(m), got)
+		}
+		if j.step() != 0 {
+			t.Errorf("%s: the journal recorded it", j.describe(m))
+		}
+	}
+}
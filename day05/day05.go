@@ -1,10 +1,14 @@
-package main
+package day05
 
 import (
 	"bufio"
+	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
 type move struct {
@@ -13,17 +17,23 @@ type move struct {
 	dest   int
 }
 
-type board [10][]byte
-
 type game struct {
 	board board
 	moves []move
 }
 
+// render_mode draws the board "end" of the game, or after "every" move.  empty draws nothing
+var render_mode = ""
+
+// crane_model overrides the crane both parts use, see parseCrane
+var crane_model = ""
+
+// inspect_step, when it isn't -1, draws the board as it was after that many moves once the game is over
+var inspect_step = -1
+
 func getResult(b *board) string {
 	result := ""
-	for i := range b {
-		t := b[i]
+	for _, t := range b.stacks {
 		if len(t) > 0 {
 			result += string(t[0])
 		}
@@ -31,98 +41,139 @@ func getResult(b *board) string {
 	return result
 }
 
-func readMove(line string) move {
+func readMove(b *board, line string) (move, error) {
 	m := move{}
 	parts := strings.Split(line, " ")
-	m.qty, _ = strconv.Atoi(parts[1])
-	m.source, _ = strconv.Atoi(parts[3])
-	m.dest, _ = strconv.Atoi(parts[5])
+	if len(parts) != 6 || parts[0] != "move" || parts[2] != "from" || parts[4] != "to" {
+		return m, fmt.Errorf("expected move N from A to B, got %q", line)
+	}
 
-	// 0-index for easier slice access
-	m.source--
-	m.dest--
+	var err error
+	if m.qty, err = strconv.Atoi(parts[1]); err != nil {
+		return m, fmt.Errorf("%q: %w", line, err)
+	}
+	// stacks are referred to by label, but kept by index for easier slice access
+	if m.source, err = b.index(parts[3]); err != nil {
+		return m, fmt.Errorf("%q: %w", line, err)
+	}
+	if m.dest, err = b.index(parts[5]); err != nil {
+		return m, fmt.Errorf("%q: %w", line, err)
+	}
 
-	return m
+	return m, nil
 }
 
-func readGame(file *os.File) *game {
+func readGame(file io.Reader) (*game, error) {
 	sc := bufio.NewScanner(file)
 	g := game{}
+	rows := []string{}
+	drawing := true
 	for sc.Scan() {
 		line := sc.Text()
 		if line == "" {
 			continue
 		}
 
-		if line[1] == '1' {
-			continue
-		}
+		if drawing {
+			if !isLabelRow(line) {
+				rows = append(rows, line)
+				continue
+			}
 
-		if strings.HasPrefix(line, "move") {
-			g.moves = append(g.moves, readMove(line))
+			b, err := parseBoard(rows, line)
+			if err != nil {
+				return nil, err
+			}
+			g.board = b
+			drawing = false
 			continue
 		}
 
-		for i := 1; i < len(line); i += 4 {
-			r := line[i]
-			if r != ' ' {
-				tower := (i - 1) / 4
-				g.board[tower] = append(g.board[tower], r)
-			}
+		m, err := readMove(&g.board, line)
+		if err != nil {
+			return nil, err
 		}
+		g.moves = append(g.moves, m)
 	}
 
-	return &g
+	if drawing {
+		return nil, fmt.Errorf("no row of stack labels under the drawing")
+	}
+	return &g, nil
 }
 
-func executePart2Move(b *board, m move) {
-	blocks_to_move := append([]byte{}, b[m.source][:m.qty]...)
-	b[m.source] = b[m.source][m.qty:]
-	b[m.dest] = append(blocks_to_move, b[m.dest]...)
-}
+// play runs every move with the crane, drawing the board along the way if render_mode asks for it
+func play(file io.Reader, c crane) (string, error) {
+	if crane_model != "" {
+		var err error
+		if c, err = parseCrane(crane_model); err != nil {
+			return "", err
+		}
+	}
 
-func executePart1Move(b *board, m move) {
-	// part 1 can only move blocks 1 at a time, which is equivalent to moving all the blocks at once in reverse order
-	// pull the blocks to move out of the source by tower, one by one, in reverse order
-	blocks_to_move := make([]byte, m.qty)
-	for i := m.qty - 1; i >= 0; i-- {
-		dest_i := m.qty - i - 1
-		blocks_to_move[dest_i] = b[m.source][i]
+	game, err := readGame(file)
+	if err != nil {
+		return "", err
 	}
 
-	b[m.source] = b[m.source][m.qty:]
-	b[m.dest] = append(blocks_to_move, b[m.dest]...)
-}
+	j := newJournal(&game.board, c)
+	if render_mode == "every" {
+		fmt.Printf("Using a %s\n\n", c)
+		game.board.render(os.Stdout)
+	}
+	for _, m := range game.moves {
+		if err := j.apply(m); err != nil {
+			return "", fmt.Errorf("move %d: %w", j.step()+1, err)
+		}
+		if render_mode == "every" {
+			fmt.Printf("\n%s\n\n", j.describe(m))
+			game.board.render(os.Stdout)
+		}
+	}
+	if render_mode == "end" {
+		game.board.render(os.Stdout)
+	}
+	result := getResult(&game.board)
 
-func part1(file *os.File) {
-	game := readGame(file)
-	for _, move := range game.moves {
-		executePart1Move(&game.board, move)
+	if inspect_step != -1 {
+		if err := j.seek(inspect_step); err != nil {
+			return "", err
+		}
+		fmt.Printf("After %d moves:\n", inspect_step)
+		game.board.render(os.Stdout)
 	}
-	println(getResult(&game.board))
+
+	return result, nil
 }
 
-func part2(file *os.File) {
-	game := readGame(file)
-	for _, move := range game.moves {
-		executePart2Move(&game.board, move)
+func part1(file io.Reader) any {
+	result, err := play(file, crateMover9000{})
+	if err != nil {
+		panic(err)
 	}
-	println(getResult(&game.board))
+	return result
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
+func part2(file io.Reader) any {
+	result, err := play(file, crateMover9001{})
+	if err != nil {
+		panic(err)
 	}
+	return result
+}
 
-	file, _ := os.Open(filename)
-	defer file.Close()
-	method(file)
+func init() {
+	aoc.Register(aoc.Day{
+		Year:     2022,
+		Number:   5,
+		Title:    "Supply Stacks",
+		Input:    "day05/input.txt",
+		Examples: []string{"day05/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+		Params: []aoc.Param{
+			aoc.StringParam("render", &render_mode, "draw the stacks at the end of the game or after every move, as end or every"),
+			aoc.StringParam("crane", &crane_model, "crane for both parts instead of their own, as 9000, 9001 or max:N"),
+			aoc.AtLeast(-1, aoc.IntParam("step", &inspect_step, "draw the stacks as they were after this many moves, -1 for none")),
+		},
+	})
 }
@@ -1,53 +1,71 @@
-package main
+package day06
 
 import (
-	"bufio"
-	"os"
-	"strings"
+	"fmt"
+	"io"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
-func indexOfUniqueStretch(message string, unique_len int) int {
-	for cursor := unique_len - 1; cursor < len(message); cursor++ {
-		chars := map[rune]bool{}
-		for i := cursor - unique_len + 1; i <= cursor; i++ {
-			chars[rune(message[i])] = true
-		}
+var packet_marker_len = 4
+var message_marker_len = 14
 
-		if len(chars) == unique_len {
-			return cursor + 1
-		}
-	}
+// marker_lens are the lengths the markers mode reports every marker for
+var marker_lens = []int{4, 14}
 
-	return -1
+func part1(file io.Reader) any {
+	offset, err := firstMarker(file, packet_marker_len)
+	if err != nil {
+		panic(err)
+	}
+	return offset
 }
 
-func part1(file *os.File) {
-	sc := bufio.NewScanner(file)
-	sc.Scan()
-
-	println(indexOfUniqueStretch(sc.Text(), 4))
+func part2(file io.Reader) any {
+	offset, err := firstMarker(file, message_marker_len)
+	if err != nil {
+		panic(err)
+	}
+	return offset
 }
 
-func part2(file *os.File) {
-	sc := bufio.NewScanner(file)
-	sc.Scan()
-
-	println(indexOfUniqueStretch(sc.Text(), 14))
-}
+// markers prints every marker of every length in marker_lens as it's found, then how many there
+// were of each length, returning the total.  only the counts are kept, so a long stream doesn't
+// pile up markers in memory
+func markers(file io.Reader) any {
+	counts := map[int]int{}
+	total := 0
+	err := Detect(file, marker_lens, func(m Marker) bool {
+		fmt.Printf("length %d marker at line %d, offset %d\n", m.Length, m.Line, m.Offset)
+		counts[m.Length]++
+		total++
+		return true
+	})
+	if err != nil {
+		panic(err)
+	}
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
+	for _, length := range marker_lens {
+		fmt.Printf("%d markers of length %d\n", counts[length], length)
 	}
+	return total
+}
 
-	file, _ := os.Open(filename)
-	defer file.Close()
-	method(file)
+func init() {
+	aoc.Register(aoc.Day{
+		Year:     2022,
+		Number:   6,
+		Title:    "Tuning Trouble",
+		Input:    "day06/input.txt",
+		Examples: []string{"day06/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+		Modes: []aoc.Mode{
+			{Name: "markers", Usage: "report every marker rather than just the first", Run: markers},
+		},
+		Params: []aoc.Param{
+			aoc.AtLeast(1, aoc.IntParam("packet-length", &packet_marker_len, "distinct characters in a start-of-packet marker")),
+			aoc.AtLeast(1, aoc.IntParam("message-length", &message_marker_len, "distinct characters in a start-of-message marker")),
+			aoc.AtLeast(1, aoc.IntsParam("lengths", &marker_lens, "marker lengths the markers mode looks for")),
+		},
+	})
 }
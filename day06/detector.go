@@ -0,0 +1,111 @@
+package day06
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Marker is a stretch of Length distinct bytes ending Offset bytes into a line, the way the puzzle
+// counts them.  lines are counted from 1, and each one is its own datastream
+type Marker struct {
+	Length int
+	Line   int
+	Offset int
+}
+
+// Detector finds markers of several lengths at once in a single pass.  rather than checking each
+// window, it tracks the longest run of distinct bytes ending at the current byte, which only
+// needs the last position each byte value was seen at.  any window up to the run's length is a marker
+type Detector struct {
+	lengths   []int
+	last      [256]int
+	pos       int
+	lineStart int
+	runStart  int
+	line      int
+}
+
+// NewDetector looks for markers of each of the lengths.  a length given twice is only looked for once
+func NewDetector(lengths ...int) *Detector {
+	sorted := append([]int{}, lengths...)
+	sort.Ints(sorted)
+	d := &Detector{line: 1}
+	for i, length := range sorted {
+		if i == 0 || length != sorted[i-1] {
+			d.lengths = append(d.lengths, length)
+		}
+	}
+	d.lineStart = 1
+	d.runStart = 1
+	return d
+}
+
+// Feed feeds the next byte of the stream through, calling found for every marker ending at it.
+// it stops early and returns false if found does
+func (d *Detector) Feed(b byte, found func(Marker) bool) bool {
+	if b == '\r' {
+		return true
+	}
+	if b == '\n' {
+		d.line++
+		d.lineStart = d.pos + 1
+		d.runStart = d.lineStart
+		return true
+	}
+
+	d.pos++
+	// positions are absolute, so anything seen on an earlier line is already before runStart
+	if d.last[b] >= d.runStart {
+		d.runStart = d.last[b] + 1
+	}
+	d.last[b] = d.pos
+
+	run := d.pos - d.runStart + 1
+	for _, length := range d.lengths {
+		if length > run {
+			break
+		}
+		if !found(Marker{Length: length, Line: d.line, Offset: d.pos - d.lineStart + 1}) {
+			return false
+		}
+	}
+	return true
+}
+
+// Detect streams r through a detector, so the input is never held in memory
+func Detect(r io.Reader, lengths []int, found func(Marker) bool) error {
+	d := NewDetector(lengths...)
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !d.Feed(b, found) {
+			return nil
+		}
+	}
+}
+
+// firstMarker is the offset of the first marker of the given length on the first line
+func firstMarker(r io.Reader, length int) (int, error) {
+	offset := -1
+	err := Detect(r, []int{length}, func(m Marker) bool {
+		if m.Line == 1 {
+			offset = m.Offset
+		}
+		return false
+	})
+	if err != nil {
+		return 0, err
+	}
+	if offset == -1 {
+		return 0, fmt.Errorf("no marker of length %d on the first line", length)
+	}
+	return offset, nil
+}
@@ -0,0 +1,125 @@
+package day06
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestFirstMarker(t *testing.T) {
+	tests := []struct {
+		input   string
+		length  int
+		want    int
+		wantErr bool
+	}{
+		{"mjqjpqmgbljsphdztnvjfqwrcgsmlb", 4, 7, false},
+		{"bvwbjplbgvbhsrlpgdmjqwftvncz", 4, 5, false},
+		{"nppdvjthqldpwncqszvftbrmjlhg", 4, 6, false},
+		{"nznrnfrfntjfmvfwmzdfjlvtqnbhcprsg", 4, 10, false},
+		{"zcfzfwzzqfrljwzlrfnpqdbhtmscgvjw", 4, 11, false},
+		{"mjqjpqmgbljsphdztnvjfqwrcgsmlb", 14, 19, false},
+		{"bvwbjplbgvbhsrlpgdmjqwftvncz", 14, 23, false},
+		{"zcfzfwzzqfrljwzlrfnpqdbhtmscgvjw", 14, 26, false},
+		{"abcd", 4, 4, false},
+		{"abcd\r\n", 4, 4, false},
+		{"abc", 4, 0, true},
+		{"aaaa\nabcd", 4, 0, true},
+		{"", 1, 0, true},
+	}
+
+	for _, test := range tests {
+		got, err := firstMarker(strings.NewReader(test.input), test.length)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q length %d: expected an error, got %d", test.input, test.length, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q length %d: %v", test.input, test.length, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%q length %d: got %d, want %d", test.input, test.length, got, test.want)
+		}
+	}
+}
+
+// markersByWindow finds every marker by checking each window on its own, the slow way
+func markersByWindow(input string, lengths []int) []Marker {
+	markers := []Marker{}
+	for l, line := range strings.Split(input, "\n") {
+		for end := 1; end <= len(line); end++ {
+			for _, length := range lengths {
+				if length > end {
+					continue
+				}
+				seen := map[byte]bool{}
+				for i := end - length; i < end; i++ {
+					seen[line[i]] = true
+				}
+				if len(seen) == length {
+					markers = append(markers, Marker{Length: length, Line: l + 1, Offset: end})
+				}
+			}
+		}
+	}
+	return markers
+}
+
+func TestDetectMatchesWindows(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	tests := []struct {
+		alphabet string
+		lengths  []int
+	}{
+		{"ab", []int{1, 2}},
+		{"abcd", []int{2, 3, 4}},
+		{"abcdefgh", []int{4, 4, 14}},
+		{"abcdefghijklmnop", []int{14, 4}},
+	}
+
+	for _, test := range tests {
+		for round := 0; round < 20; round++ {
+			lines := make([]string, 1+rng.Intn(3))
+			for i := range lines {
+				line := make([]byte, rng.Intn(60))
+				for j := range line {
+					line[j] = test.alphabet[rng.Intn(len(test.alphabet))]
+				}
+				lines[i] = string(line)
+			}
+			input := strings.Join(lines, "\n")
+
+			// a repeated length is only looked for once, and markers come out shortest first
+			sorted := append([]int{}, test.lengths...)
+			sort.Ints(sorted)
+			lengths := []int{}
+			for _, length := range sorted {
+				if len(lengths) == 0 || lengths[len(lengths)-1] != length {
+					lengths = append(lengths, length)
+				}
+			}
+			want := markersByWindow(input, lengths)
+
+			got := []Marker{}
+			err := Detect(strings.NewReader(input), test.lengths, func(m Marker) bool {
+				got = append(got, m)
+				return true
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("%q lengths %v: got %d markers, want %d", input, test.lengths, len(got), len(want))
+			}
+			for i := range got {
+				if got[i] != want[i] {
+					t.Fatalf("%q lengths %v: marker %d is %+v, want %+v", input, test.lengths, i, got[i], want[i])
+				}
+			}
+		}
+	}
+}
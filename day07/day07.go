@@ -1,163 +1,101 @@
-package main
+package day07
 
 import (
-	"bufio"
-	"fmt"
+	"io"
 	"os"
-	"strconv"
 	"strings"
-)
-
-type file struct {
-	name string
-	size int
-}
-
-type directory struct {
-	name    string
-	parent  *directory
-	files   []*file
-	subdirs []*directory
-}
 
-func getDirSize(d *directory) int {
-	size := 0
-	for _, f := range d.files {
-		size += f.size
-	}
-	for _, sd := range d.subdirs {
-		size += getDirSize(sd)
-	}
-	return size
-}
-
-func printDirectory(d *directory, indent int) {
-	print(strings.Repeat(" ", indent))
-	println("-", d.name, "(dir)")
-	indent += 2
-	for _, f := range d.files {
-		print(strings.Repeat(" ", indent))
-		println("-", f.name, "(file, size="+fmt.Sprintf("%d", f.size)+")")
-	}
-	for _, sd := range d.subdirs {
-		printDirectory(sd, indent)
-	}
-}
-
-func (d *directory) print() {
-	printDirectory(d, 0)
-}
-
-func parseFilesystem(f *os.File) directory {
-	rootdir := directory{name: "/"}
-	cwd := &rootdir
+	"citro.net/advent-2022-go/aoc"
+)
 
-	sc := bufio.NewScanner(f)
-	for sc.Scan() {
-		line := sc.Text()
-		if line == "" {
-			continue
-		}
+var max_dir_size = 100000
+var fs_size = 70000000
+var space_req = 30000000
 
-		if strings.HasPrefix(line, "$") {
-			command := line[2:]
-			if command == "cd /" {
-				cwd = &rootdir
-			} else if command == "cd .." {
-				cwd = cwd.parent
-			} else if strings.HasPrefix(command, "cd ") {
-				new_dir_name := command[3:]
-				for _, sd := range cwd.subdirs {
-					if sd.name == new_dir_name {
-						cwd = sd
-						break
-					}
-				}
-			}
-		} else if strings.HasPrefix(line, "dir") {
-			dir_name := line[4:]
-			new_dir := directory{name: dir_name, parent: cwd}
-			cwd.subdirs = append(cwd.subdirs, &new_dir)
-		} else {
-			split := strings.Split(line, " ")
-			size, _ := strconv.Atoi(split[0])
-			name := split[1]
-			new_file := file{name: name, size: size}
-			cwd.files = append(cwd.files, &new_file)
-		}
-	}
-
-	return rootdir
-}
+// the shell runs shell_commands, separated by semicolons, unless shell_script names a file of
+// commands to run instead, or - to read them interactively from stdin
+var shell_commands = "tree"
+var shell_script = ""
 
 func findDirsUnderSize(d *directory, max_size int) []*directory {
 	dirs := []*directory{}
-	s := getDirSize(d)
-	if s <= max_size {
-		dirs = append(dirs, d)
-	}
-
-	for _, sd := range d.subdirs {
-		dirs = append(dirs, findDirsUnderSize(sd, max_size)...)
-	}
+	d.walk(func(d *directory) {
+		if d.size <= max_size {
+			dirs = append(dirs, d)
+		}
+	})
 
 	return dirs
 }
 
-func part1(file *os.File) {
-	dir := parseFilesystem(file)
+func part1(file io.Reader) any {
+	dir, _ := parseFilesystem(file)
 
-	dirs := findDirsUnderSize(&dir, 100000)
+	dirs := findDirsUnderSize(dir, max_dir_size)
 	accum := 0
 	for _, d := range dirs {
-		accum += getDirSize(d)
+		accum += d.size
 	}
-	println(accum)
+	return accum
 }
 
-func part2(file *os.File) {
-	dir := parseFilesystem(file)
-
-	fs_size := 70000000
-	space_req := 30000000
-	space_used := getDirSize(&dir)
-	space_avail := fs_size - space_used
-	delete_size_req := space_req - space_avail
-
-	delete_size := space_used
-
-	dirs_to_search := []*directory{&dir}
-	for len(dirs_to_search) > 0 {
-		d := dirs_to_search[0]
-		dirs_to_search = dirs_to_search[1:]
+func part2(file io.Reader) any {
+	dir, _ := parseFilesystem(file)
 
-		s := getDirSize(d)
-		if s < delete_size_req {
-			continue
-		}
-		if s < delete_size {
-			delete_size = s
-		}
-
-		dirs_to_search = append(dirs_to_search, d.subdirs...)
+	needed, candidates := deletionCandidates(dir, space_req)
+	if needed <= 0 {
+		return 0
 	}
-
-	println(delete_size)
+	if len(candidates) == 0 {
+		return nil
+	}
+	return candidates[0].size
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
+// runShell opens a shell over the filesystem from the transcript
+func runShell(file io.Reader) any {
+	dir, _ := parseFilesystem(file)
+	sh := newShell(dir, os.Stdout)
+
+	var err error
+	if shell_script == "-" {
+		err = sh.runScript(os.Stdin, true)
+	} else if shell_script != "" {
+		var script *os.File
+		if script, err = os.Open(shell_script); err == nil {
+			err = sh.runScript(script, false)
+			script.Close()
 		}
+	} else {
+		err = sh.runScript(strings.NewReader(strings.ReplaceAll(shell_commands, ";", "\n")), false)
+	}
+
+	if err != nil {
+		panic(err)
 	}
+	return nil
+}
 
-	file, _ := os.Open(filename)
-	defer file.Close()
-	method(file)
+func init() {
+	aoc.Register(aoc.Day{
+		Year:     2022,
+		Number:   7,
+		Title:    "No Space Left On Device",
+		Input:    "day07/input.txt",
+		Examples: []string{"day07/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+		Modes: []aoc.Mode{
+			{Name: "shell", Usage: "a shell for exploring the filesystem", Run: runShell},
+		},
+		Capabilities: aoc.Visualisable,
+		Params: []aoc.Param{
+			aoc.AtLeast(0, aoc.IntParam("max-dir-size", &max_dir_size, "largest directory counted by part 1")),
+			aoc.AtLeast(0, aoc.IntParam("disk-size", &fs_size, "total disk space on the device")),
+			aoc.AtLeast(0, aoc.IntParam("space-needed", &space_req, "unused space the update needs")),
+			aoc.Local(aoc.StringParam("commands", &shell_commands, "commands for the shell, separated by semicolons")),
+			aoc.Local(aoc.StringParam("script", &shell_script, "file of commands for the shell instead, or - to type them in")),
+		},
+		Validate: validatePuzzle,
+		Generate: generate,
+	})
 }
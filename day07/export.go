@@ -0,0 +1,145 @@
+package day07
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type jsonFile struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Size int    `json:"size"`
+}
+
+type jsonDir struct {
+	Name  string     `json:"name"`
+	Path  string     `json:"path"`
+	Size  int        `json:"size"`
+	Files []jsonFile `json:"files"`
+	Dirs  []jsonDir  `json:"dirs"`
+}
+
+func toJSON(d *directory) jsonDir {
+	j := jsonDir{Name: d.name, Path: d.path, Size: d.size, Files: []jsonFile{}, Dirs: []jsonDir{}}
+	for _, f := range d.files {
+		j.Files = append(j.Files, jsonFile{f.name, f.path, f.size})
+	}
+	for _, sd := range d.subdirs {
+		j.Dirs = append(j.Dirs, toJSON(sd))
+	}
+	return j
+}
+
+// exportJSON writes the tree under d as nested json, with every directory's total size
+func exportJSON(w io.Writer, d *directory) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(toJSON(d))
+}
+
+// materialise recreates the tree under d inside target, which must not exist yet or be empty.
+// files are sparse, so they have the recorded sizes without taking up the disk space, and
+// `du --apparent-size` and `find -size` see the same sizes the puzzle does
+func materialise(d *directory, target string) error {
+	return materialiseUnder(d, filepath.Clean(target), filepath.Clean(target))
+}
+
+// under joins name onto dir, making sure the result can't leave root
+func under(root string, dir string, name string) (string, error) {
+	p := filepath.Join(dir, name)
+	rel, err := filepath.Rel(root, p)
+	if !validName(name) || err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%q would be outside %s", name, root)
+	}
+	return p, nil
+}
+
+func materialiseUnder(d *directory, target string, root string) error {
+	entries, err := os.ReadDir(target)
+	if err == nil && len(entries) > 0 {
+		return fmt.Errorf("%s isn't empty", target)
+	}
+
+	if err := os.MkdirAll(target, 0755); err != nil {
+		return err
+	}
+	for _, f := range d.files {
+		p, err := under(root, target, f.name)
+		if err != nil {
+			return err
+		}
+		out, err := os.Create(p)
+		if err != nil {
+			return err
+		}
+		err = out.Truncate(int64(f.size))
+		out.Close()
+		if err != nil {
+			return err
+		}
+	}
+	for _, sd := range d.subdirs {
+		p, err := under(root, target, sd.name)
+		if err != nil {
+			return err
+		}
+		if err := materialiseUnder(sd, p, root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateTranscript writes the terminal session that would explore a real directory tree, in
+// the same form as the puzzle input.  anything other than plain files and directories is skipped
+func generateTranscript(w io.Writer, root string) error {
+	fmt.Fprintln(w, "$ cd /")
+	return transcribe(w, root)
+}
+
+func transcribe(w io.Writer, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "$ ls")
+	subdirs := []string{}
+	for _, e := range entries {
+		if e.IsDir() {
+			fmt.Fprintf(w, "dir %s\n", e.Name())
+			subdirs = append(subdirs, e.Name())
+			continue
+		}
+		if !e.Type().IsRegular() {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%d %s\n", info.Size(), e.Name())
+	}
+
+	for _, name := range subdirs {
+		fmt.Fprintf(w, "$ cd %s\n", name)
+		if err := transcribe(w, filepath.Join(dir, name)); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "$ cd ..")
+	}
+	return nil
+}
+
+// generate is day 7's input generator, turning the directory tree at args[0] into a transcript
+func generate(w io.Writer, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected the directory to transcribe")
+	}
+	return generateTranscript(w, args[0])
+}
@@ -0,0 +1,317 @@
+package day07
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// shell runs commands against a rebuilt filesystem.  it's read only, so paths always resolve
+// against the tree as the transcript left it
+type shell struct {
+	root *directory
+	cwd  *directory
+	out  io.Writer
+}
+
+func newShell(root *directory, out io.Writer) *shell {
+	return &shell{root: root, cwd: root, out: out}
+}
+
+var shellHelp = `cd <dir>                    change directory
+pwd                         print the current directory
+ls [dir]                    list a directory like the transcript does
+tree [dir]                  draw a directory and everything under it
+du [dir]                    total size of every directory, children first
+find [dir] [-type d|f] [-size [+-]N]
+                            paths under dir, optionally bigger (+N), smaller (-N) or exactly N
+candidates [free]           directories that would free enough space for the update, smallest first
+json <file|-> [dir]         export a directory and everything under it as json
+materialise <target> [dir]  recreate a directory under target on the real disk, with sparse files
+help                        this message
+exit                        stop reading commands`
+
+// errExit stops a script early
+var errExit = fmt.Errorf("exit")
+
+// resolve finds the directory or file at p, which can be absolute or relative to the cwd
+func (s *shell) resolve(p string) (*directory, *file, error) {
+	if p == "" {
+		return s.cwd, nil, nil
+	}
+
+	d := s.cwd
+	if strings.HasPrefix(p, "/") {
+		d = s.root
+	}
+	parts := strings.Split(strings.Trim(p, "/"), "/")
+	for i, part := range parts {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			if d.parent != nil {
+				d = d.parent
+			}
+			continue
+		}
+
+		if sd := d.subdir(part); sd != nil {
+			d = sd
+			continue
+		}
+		if f := d.file(part); f != nil && i == len(parts)-1 {
+			return nil, f, nil
+		}
+		return nil, nil, fmt.Errorf("%s: no such directory", path.Join(d.path, part))
+	}
+	return d, nil, nil
+}
+
+func (s *shell) resolveDir(p string) (*directory, error) {
+	d, f, err := s.resolve(p)
+	if err != nil {
+		return nil, err
+	}
+	if f != nil {
+		return nil, fmt.Errorf("%s: not a directory", f.path)
+	}
+	return d, nil
+}
+
+// run executes a single command line
+func (s *shell) run(line string) error {
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		return nil
+	}
+	arg := func(i int) string {
+		if i < len(args) {
+			return args[i]
+		}
+		return ""
+	}
+
+	switch args[0] {
+	case "cd":
+		target := arg(1)
+		if target == "" {
+			target = "/"
+		}
+		d, err := s.resolveDir(target)
+		if err != nil {
+			return err
+		}
+		s.cwd = d
+	case "pwd":
+		fmt.Fprintln(s.out, s.cwd.path)
+	case "ls":
+		d, f, err := s.resolve(arg(1))
+		if err != nil {
+			return err
+		}
+		if f != nil {
+			fmt.Fprintf(s.out, "%d %s\n", f.size, f.name)
+			return nil
+		}
+		for _, sd := range d.subdirs {
+			fmt.Fprintf(s.out, "dir %s\n", sd.name)
+		}
+		for _, f := range d.files {
+			fmt.Fprintf(s.out, "%d %s\n", f.size, f.name)
+		}
+	case "tree":
+		d, err := s.resolveDir(arg(1))
+		if err != nil {
+			return err
+		}
+		printDirectory(s.out, d, 0)
+	case "du":
+		d, err := s.resolveDir(arg(1))
+		if err != nil {
+			return err
+		}
+		s.du(d)
+	case "find":
+		return s.find(args[1:])
+	case "candidates":
+		free := space_req
+		if arg(1) != "" {
+			var err error
+			if free, err = strconv.Atoi(arg(1)); err != nil {
+				return fmt.Errorf("candidates: %w", err)
+			}
+		}
+		s.candidates(free)
+	case "json":
+		return s.exportJSON(arg(1), arg(2))
+	case "materialise":
+		if arg(1) == "" {
+			return fmt.Errorf("materialise: needs a target directory")
+		}
+		d, err := s.resolveDir(arg(2))
+		if err != nil {
+			return err
+		}
+		if err := materialise(d, arg(1)); err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "%s is now at %s\n", d.path, arg(1))
+	case "help":
+		fmt.Fprintln(s.out, shellHelp)
+	case "exit":
+		return errExit
+	default:
+		return fmt.Errorf("%s: unknown command, try help", args[0])
+	}
+	return nil
+}
+
+func (s *shell) exportJSON(target string, dir string) error {
+	if target == "" {
+		return fmt.Errorf("json: needs a file to write to, or -")
+	}
+	d, err := s.resolveDir(dir)
+	if err != nil {
+		return err
+	}
+	if target == "-" {
+		return exportJSON(s.out, d)
+	}
+
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	return exportJSON(out, d)
+}
+
+func (s *shell) du(d *directory) {
+	for _, sd := range d.subdirs {
+		s.du(sd)
+	}
+	fmt.Fprintf(s.out, "%d\t%s\n", d.size, d.path)
+}
+
+// sizeTest reads find's -size argument, +N for bigger than N, -N for smaller, or exactly N
+func sizeTest(arg string) (func(size int) bool, error) {
+	n, err := strconv.Atoi(strings.TrimLeft(arg, "+-"))
+	if err != nil {
+		return nil, fmt.Errorf("find: invalid size %q", arg)
+	}
+	switch arg[0] {
+	case '+':
+		return func(size int) bool { return size > n }, nil
+	case '-':
+		return func(size int) bool { return size < n }, nil
+	}
+	return func(size int) bool { return size == n }, nil
+}
+
+func (s *shell) find(args []string) error {
+	start := ""
+	kind := ""
+	sizeOk := func(int) bool { return true }
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-type", "-size":
+			if i+1 == len(args) {
+				return fmt.Errorf("find: %s needs a value", args[i])
+			}
+			if args[i] == "-type" {
+				kind = args[i+1]
+				if kind != "d" && kind != "f" {
+					return fmt.Errorf("find: -type is d or f, not %s", kind)
+				}
+			} else {
+				var err error
+				if sizeOk, err = sizeTest(args[i+1]); err != nil {
+					return err
+				}
+			}
+			i++
+		default:
+			start = args[i]
+		}
+	}
+
+	d, err := s.resolveDir(start)
+	if err != nil {
+		return err
+	}
+	d.walk(func(d *directory) {
+		if kind != "f" && sizeOk(d.size) {
+			fmt.Fprintf(s.out, "%d\t%s\n", d.size, d.path)
+		}
+		if kind != "d" {
+			for _, f := range d.files {
+				if sizeOk(f.size) {
+					fmt.Fprintf(s.out, "%d\t%s\n", f.size, f.path)
+				}
+			}
+		}
+	})
+	return nil
+}
+
+// deletionCandidates is every directory big enough that deleting it gets the disk to free bytes unused, smallest first
+func deletionCandidates(root *directory, free int) (needed int, candidates []*directory) {
+	needed = free - (fs_size - root.size)
+	if needed <= 0 {
+		return needed, nil
+	}
+
+	root.walk(func(d *directory) {
+		if d.size >= needed {
+			candidates = append(candidates, d)
+		}
+	})
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].size < candidates[j].size
+	})
+	return needed, candidates
+}
+
+func (s *shell) candidates(free int) {
+	needed, candidates := deletionCandidates(s.root, free)
+	if needed <= 0 {
+		fmt.Fprintf(s.out, "%d of %d used, there's already %d free\n", s.root.size, fs_size, fs_size-s.root.size)
+		return
+	}
+
+	fmt.Fprintf(s.out, "%d of %d used, %d more needs freeing to get %d free\n", s.root.size, fs_size, needed, free)
+	for _, d := range candidates {
+		fmt.Fprintf(s.out, "%d\t%s\n", d.size, d.path)
+	}
+}
+
+// runScript runs every line of script.  with a prompt it behaves interactively, reporting errors
+// and carrying on, otherwise the first error stops the script
+func (s *shell) runScript(script io.Reader, prompt bool) error {
+	sc := bufio.NewScanner(script)
+	for {
+		if prompt {
+			fmt.Fprintf(s.out, "%s$ ", s.cwd.path)
+		}
+		if !sc.Scan() {
+			return sc.Err()
+		}
+
+		err := s.run(sc.Text())
+		if err == errExit {
+			return nil
+		}
+		if err != nil && !prompt {
+			return err
+		}
+		if err != nil {
+			fmt.Fprintln(s.out, err)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package day07
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type file struct {
+	name string
+	path string
+	size int
+}
+
+// directory sizes are worked out once the whole transcript has been read, see computeSizes
+type directory struct {
+	name    string
+	path    string
+	parent  *directory
+	files   []*file
+	subdirs []*directory
+	size    int
+
+	// listing is what the transcript's ls showed, so a second ls can be checked against it
+	listing []string
+}
+
+func newDirectory(name string, parent *directory) *directory {
+	d := &directory{name: name, parent: parent, path: "/"}
+	if parent != nil {
+		d.path = path.Join(parent.path, name)
+	}
+	return d
+}
+
+// validName is whether name can be a single file or directory name, which rules out anything that
+// would lead somewhere else if it were joined onto a path
+func validName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.Contains(name, "/")
+}
+
+func (d *directory) subdir(name string) *directory {
+	for _, sd := range d.subdirs {
+		if sd.name == name {
+			return sd
+		}
+	}
+	return nil
+}
+
+func (d *directory) file(name string) *file {
+	for _, f := range d.files {
+		if f.name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func (d *directory) addSubdir(name string) *directory {
+	sd := newDirectory(name, d)
+	d.subdirs = append(d.subdirs, sd)
+	return sd
+}
+
+func (d *directory) addFile(name string, size int) *file {
+	f := &file{name: name, path: path.Join(d.path, name), size: size}
+	d.files = append(d.files, f)
+	return f
+}
+
+// computeSizes fills in the size of every directory in a single walk of the tree
+func (d *directory) computeSizes() int {
+	d.size = 0
+	for _, f := range d.files {
+		d.size += f.size
+	}
+	for _, sd := range d.subdirs {
+		d.size += sd.computeSizes()
+	}
+	return d.size
+}
+
+// walk visits d and every directory under it, parents before their children
+func (d *directory) walk(fn func(d *directory)) {
+	fn(d)
+	for _, sd := range d.subdirs {
+		sd.walk(fn)
+	}
+}
+
+func printDirectory(w io.Writer, d *directory, indent int) {
+	fmt.Fprintf(w, "%s- %s (dir, size=%d)\n", strings.Repeat(" ", indent), d.name, d.size)
+	indent += 2
+	for _, f := range d.files {
+		fmt.Fprintf(w, "%s- %s (file, size=%d)\n", strings.Repeat(" ", indent), f.name, f.size)
+	}
+	for _, sd := range d.subdirs {
+		printDirectory(w, sd, indent)
+	}
+}
+
+// parseFilesystem rebuilds the filesystem from a terminal transcript.  it carries on past anything
+// odd in the transcript, like a cd into a directory no ls has shown or a directory listed twice with
+// different contents, and returns a description of each problem alongside the filesystem
+func parseFilesystem(f io.Reader) (*directory, []string) {
+	rootdir := newDirectory("/", nil)
+	cwd := rootdir
+	problems := []string{}
+	problem := func(line int, format string, args ...any) {
+		problems = append(problems, fmt.Sprintf("line %d: ", line)+fmt.Sprintf(format, args...))
+	}
+
+	// the ls output being read, which is only applied once it's complete
+	var listing []string
+	listingStart := 0
+	endListing := func() {
+		if listing != nil {
+			applyListing(cwd, listing, func(format string, args ...any) { problem(listingStart, format, args...) })
+			listing = nil
+		}
+	}
+
+	sc := bufio.NewScanner(f)
+	line_no := 0
+	for sc.Scan() {
+		line_no++
+		line := sc.Text()
+		if line == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(line, "$ ") {
+			if listing == nil {
+				problem(line_no, "output %q without an ls before it", line)
+				continue
+			}
+			listing = append(listing, line)
+			continue
+		}
+
+		endListing()
+		command := line[2:]
+		if command == "ls" {
+			listing = []string{}
+			listingStart = line_no
+		} else if command == "cd /" {
+			cwd = rootdir
+		} else if command == "cd .." {
+			if cwd.parent == nil {
+				problem(line_no, "cd .. from the root directory")
+				continue
+			}
+			cwd = cwd.parent
+		} else if strings.HasPrefix(command, "cd ") {
+			new_dir_name := command[3:]
+			if !validName(new_dir_name) {
+				problem(line_no, "cd into %q, which isn't a directory name", new_dir_name)
+				continue
+			}
+			sd := cwd.subdir(new_dir_name)
+			if sd == nil {
+				// the transcript says it's there, so believe it, even though no ls showed it
+				problem(line_no, "cd into %s, which no ls has shown", path.Join(cwd.path, new_dir_name))
+				sd = cwd.addSubdir(new_dir_name)
+			}
+			cwd = sd
+		} else {
+			problem(line_no, "unknown command %q", command)
+		}
+	}
+	endListing()
+
+	rootdir.computeSizes()
+	return rootdir, problems
+}
+
+// applyListing adds the entries from an ls to the directory.  if the directory has been listed
+// before, the new listing has to match, otherwise it's reported and the first listing is kept
+func applyListing(d *directory, listing []string, problem func(format string, args ...any)) {
+	sorted := append([]string{}, listing...)
+	sort.Strings(sorted)
+	if d.listing != nil {
+		if strings.Join(sorted, "\n") != strings.Join(d.listing, "\n") {
+			problem("%s was listed again with different contents", d.path)
+		}
+		return
+	}
+	d.listing = sorted
+
+	for _, entry := range listing {
+		if dir_name, ok := strings.CutPrefix(entry, "dir "); ok {
+			if !validName(dir_name) {
+				problem("%q in the listing of %s isn't a directory name", dir_name, d.path)
+				continue
+			}
+			if d.file(dir_name) != nil {
+				problem("%s is listed as both a file and a directory in %s", dir_name, d.path)
+				continue
+			}
+			if d.subdir(dir_name) == nil {
+				d.addSubdir(dir_name)
+			}
+			continue
+		}
+
+		size_text, name, ok := strings.Cut(entry, " ")
+		size, err := strconv.Atoi(size_text)
+		if !ok || err != nil {
+			problem("can't read %q in the listing of %s", entry, d.path)
+			continue
+		}
+		if size < 0 {
+			problem("%s in %s has a negative size", name, d.path)
+			continue
+		}
+		if !validName(name) {
+			problem("%q in the listing of %s isn't a file name", name, d.path)
+			continue
+		}
+		if d.subdir(name) != nil {
+			problem("%s is listed as both a file and a directory in %s", name, d.path)
+			continue
+		}
+		if d.file(name) != nil {
+			problem("%s is listed twice in %s", name, d.path)
+			continue
+		}
+		d.addFile(name, size)
+	}
+}
+
+// validatePuzzle reports every problem parseFilesystem found in the transcript
+func validatePuzzle(f io.Reader, part int) error {
+	_, problems := parseFilesystem(f)
+	errs := []error{}
+	for _, p := range problems {
+		errs = append(errs, errors.New(p))
+	}
+	return errors.Join(errs...)
+}
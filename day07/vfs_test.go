@@ -0,0 +1,86 @@
+package day07
+
+import (
+	"strings"
+	"testing"
+)
+
+const introTranscript = `$ cd /
+$ ls
+dir a
+14848514 b.txt
+8504156 c.dat
+dir d
+$ cd a
+$ ls
+dir e
+29116 f
+2557 g
+62596 h.lst
+$ cd e
+$ ls
+584 i
+$ cd ..
+$ cd ..
+$ cd d
+$ ls
+4060174 j
+8033020 d.log
+5626152 d.ext
+7214296 k
+`
+
+func TestParseFilesystemSizes(t *testing.T) {
+	root, problems := parseFilesystem(strings.NewReader(introTranscript))
+	if len(problems) != 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+
+	sizes := map[string]int{}
+	root.walk(func(d *directory) {
+		sizes[d.path] = d.size
+	})
+	want := map[string]int{"/": 48381165, "/a": 94853, "/a/e": 584, "/d": 24933642}
+	if len(sizes) != len(want) {
+		t.Errorf("got directories %v, want %v", sizes, want)
+	}
+	for path, size := range want {
+		if sizes[path] != size {
+			t.Errorf("%s: got size %d, want %d", path, sizes[path], size)
+		}
+	}
+}
+
+func TestParseFilesystemProblems(t *testing.T) {
+	tests := []struct {
+		name       string
+		transcript string
+		problems   []string
+	}{
+		{"clean", "$ cd /\n$ ls\ndir a\n1 b\n$ cd a\n$ ls\n2 c\n", nil},
+		{"relisted the same", "$ ls\n1 b\n$ ls\n1 b\n", nil},
+		{"relisted differently", "$ ls\n1 b\n$ ls\n2 b\n", []string{"line 3: / was listed again with different contents"}},
+		{"file twice", "$ ls\n1 b\n2 b\n", []string{"line 1: b is listed twice in /"}},
+		{"dir then file", "$ ls\ndir a\n1 a\n", []string{"line 1: a is listed as both a file and a directory in /"}},
+		{"file then dir", "$ ls\n1 a\ndir a\n", []string{"line 1: a is listed as both a file and a directory in /"}},
+		{"cd then file", "$ cd a\n$ cd ..\n$ ls\n1 a\n", []string{
+			"line 1: cd into /a, which no ls has shown",
+			"line 3: a is listed as both a file and a directory in /",
+		}},
+		{"cd above the root", "$ cd ..\n", []string{"line 1: cd .. from the root directory"}},
+		{"output without ls", "1 b\n", []string{`line 1: output "1 b" without an ls before it`}},
+		{"bad entry", "$ ls\nlots b\n", []string{`line 1: can't read "lots b" in the listing of /`}},
+		{"parent as a dir", "$ ls\ndir ..\n", []string{`line 1: ".." in the listing of / isn't a directory name`}},
+		{"path as a file", "$ ls\n1 ../../b\n", []string{`line 1: "../../b" in the listing of / isn't a file name`}},
+		{"cd into a path", "$ cd a/b\n", []string{`line 1: cd into "a/b", which isn't a directory name`}},
+		{"negative size", "$ ls\n-1 b\n", []string{"line 1: b in / has a negative size"}},
+		{"unknown command", "$ rm b\n", []string{`line 1: unknown command "rm b"`}},
+	}
+
+	for _, test := range tests {
+		_, problems := parseFilesystem(strings.NewReader(test.transcript))
+		if strings.Join(problems, "\n") != strings.Join(test.problems, "\n") {
+			t.Errorf("%s: got problems %q, want %q", test.name, problems, test.problems)
+		}
+	}
+}
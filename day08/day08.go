@@ -1,9 +1,13 @@
-package main
+package day08
 
 import (
 	"bufio"
-	"os"
+	"fmt"
+	"io"
+	"strconv"
 	"strings"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
 type forest [][]int
@@ -15,25 +19,68 @@ var directions = [][]int{
 	{0, -1},
 }
 
-func readForest(file *os.File) *forest {
+// tree_query is the row and column of a tree to list everything it can see, empty for none
+var tree_query = []int{}
+
+// splitHeights reads a row of the forest.  the puzzle gives one digit per tree, but heights of
+// more than one digit can be separated by commas or spaces instead
+func splitHeights(line string) ([]int, error) {
+	fields := []string{}
+	if strings.Contains(line, ",") {
+		for _, field := range strings.Split(line, ",") {
+			fields = append(fields, strings.TrimSpace(field))
+		}
+	} else if strings.ContainsAny(line, " \t") {
+		fields = strings.Fields(line)
+	} else {
+		fields = strings.Split(line, "")
+	}
+
+	r := make([]int, len(fields))
+	for i, field := range fields {
+		height, err := strconv.Atoi(field)
+		if err != nil || height < 0 {
+			return nil, fmt.Errorf("invalid tree height %q", field)
+		}
+		r[i] = height
+	}
+	return r, nil
+}
+
+func readForest(file io.Reader) (*forest, error) {
 	sc := bufio.NewScanner(file)
 	f := forest{}
+	line_no := 0
 	for sc.Scan() {
+		line_no++
 		line := sc.Text()
 		if line == "" {
 			continue
 		}
 
-		r := make([]int, len(line))
-		for i, c := range line {
-			r[i] = int(c) - '0'
+		r, err := splitHeights(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line_no, err)
+		}
+		if len(f) > 0 && len(r) != len(f[0]) {
+			return nil, fmt.Errorf("line %d: %d trees in a row, but the first row has %d", line_no, len(r), len(f[0]))
 		}
 		f = append(f, r)
 	}
 
-	return &f
+	return &f, sc.Err()
 }
 
+func mustReadForest(file io.Reader) *forest {
+	f, err := readForest(file)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// isVisible and calculateScenicScore walk out from a single tree.  the parts use analyseForest
+// instead, and these are kept to check it against
 func isVisible(f *forest, r int, c int) bool {
 	h := len(*f)
 	w := len((*f)[0])
@@ -101,48 +148,76 @@ func calculateScenicScore(f *forest, r int, c int) int {
 	return score
 }
 
-func part1(file *os.File) {
-	forest := readForest(file)
+func part1(file io.Reader) any {
+	sightlines := analyseForest(mustReadForest(file))
+	if err := drawHeatmap(sightlines, sightlines.visibilityHeat()); err != nil {
+		panic(err)
+	}
+
 	visible_count := 0
-	for r := 0; r < len(*forest); r++ {
-		for c := 0; c < len((*forest)[r]); c++ {
-			if isVisible(forest, r, c) {
-				visible_count++
-			}
+	for _, visible := range sightlines.visible {
+		if visible {
+			visible_count++
 		}
 	}
 
-	println(visible_count)
+	return visible_count
 }
 
-func part2(file *os.File) {
-	forest := readForest(file)
+func part2(file io.Reader) any {
+	forest := mustReadForest(file)
+	if len(tree_query) > 0 {
+		if len(tree_query) != 2 {
+			panic(fmt.Errorf("tree needs a row and a column, not %v", tree_query))
+		}
+		if err := printLineOfSight(forest, tree_query[0], tree_query[1]); err != nil {
+			panic(err)
+		}
+	}
+
+	sightlines := analyseForest(forest)
+	if err := drawHeatmap(sightlines, sightlines.scenicHeat()); err != nil {
+		panic(err)
+	}
+
 	highest_score := 0
-	for r := 0; r < len(*forest); r++ {
-		for c := 0; c < len((*forest)[r]); c++ {
-			score := calculateScenicScore(forest, r, c)
-			if score > highest_score {
-				highest_score = score
-			}
+	for _, score := range sightlines.scenic {
+		if score > highest_score {
+			highest_score = score
 		}
 	}
 
-	println(highest_score)
+	return highest_score
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
+// verify compares the sweeps against walking out from each tree, returning how many results
+// differ.  walking is O(n³), so this is only practical on forests the size of the puzzle input
+func verify(file io.Reader) any {
+	forest := mustReadForest(file)
+	mismatches := verifySightlines(forest, analyseForest(forest))
+	for _, m := range mismatches {
+		fmt.Println(m)
 	}
 
-	file, _ := os.Open(filename)
-	defer file.Close()
-	method(file)
+	return len(mismatches)
+}
+
+func init() {
+	aoc.Register(aoc.Day{
+		Year:     2022,
+		Number:   8,
+		Title:    "Treetop Tree House",
+		Input:    "day08/input.txt",
+		Examples: []string{"day08/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+		Modes: []aoc.Mode{
+			{Name: "verify", Usage: "check the sweeps against walking out from every tree", Run: verify},
+		},
+		Generate:     generate,
+		Capabilities: aoc.Visualisable,
+		Params: []aoc.Param{
+			aoc.Local(aoc.StringParam("heatmap", &heatmap, "draw visibility (part 1) or scenic scores (part 2), as ansi or a png file to write")),
+			aoc.AtLeast(0, aoc.IntsParam("tree", &tree_query, "row,column of a tree for part 2 to list everything it can see")),
+		},
+	})
 }
@@ -0,0 +1,120 @@
+package day08
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"strings"
+)
+
+// heatmap is "ansi" to draw in the terminal, or a path to write a png to.  empty draws nothing
+var heatmap = ""
+
+// gradient runs from cold to hot, and values in between are blended
+var gradient = []color.RGBA{
+	{0x1a, 0x1a, 0x40, 0xff},
+	{0x1f, 0x6f, 0xb4, 0xff},
+	{0x2c, 0xb0, 0x5a, 0xff},
+	{0xf0, 0xd0, 0x30, 0xff},
+	{0xe0, 0x30, 0x20, 0xff},
+}
+
+// heat picks the colour for t between 0 and 1
+func heat(t float64) color.RGBA {
+	if t <= 0 {
+		return gradient[0]
+	}
+	if t >= 1 {
+		return gradient[len(gradient)-1]
+	}
+
+	pos := t * float64(len(gradient)-1)
+	i := int(pos)
+	frac := pos - float64(i)
+	a, b := gradient[i], gradient[i+1]
+	blend := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*frac)
+	}
+	return color.RGBA{blend(a.R, b.R), blend(a.G, b.G), blend(a.B, b.B), 0xff}
+}
+
+// visibilityHeat colours visible trees hot and hidden ones cold
+func (s *sightlines) visibilityHeat() []float64 {
+	values := make([]float64, len(s.visible))
+	for i, visible := range s.visible {
+		if visible {
+			values[i] = 1
+		}
+	}
+	return values
+}
+
+// scenicHeat scales scores logarithmically, since a few trees score far higher than the rest
+func (s *sightlines) scenicHeat() []float64 {
+	highest := 0
+	for _, score := range s.scenic {
+		if score > highest {
+			highest = score
+		}
+	}
+
+	values := make([]float64, len(s.scenic))
+	if highest == 0 {
+		return values
+	}
+	for i, score := range s.scenic {
+		values[i] = math.Log1p(float64(score)) / math.Log1p(float64(highest))
+	}
+	return values
+}
+
+// renderANSI draws each tree as two cells with a true colour background
+func renderANSI(w io.Writer, s *sightlines, values []float64) {
+	sb := strings.Builder{}
+	for r := 0; r < s.h; r++ {
+		for c := 0; c < s.w; c++ {
+			col := heat(values[s.at(r, c)])
+			fmt.Fprintf(&sb, "\x1b[48;2;%d;%d;%dm  ", col.R, col.G, col.B)
+		}
+		sb.WriteString("\x1b[0m\n")
+	}
+	io.WriteString(w, sb.String())
+}
+
+// renderPNG draws each tree as a square, scaled up so small forests are still easy to see
+func renderPNG(w io.Writer, s *sightlines, values []float64) error {
+	scale := 1
+	if s.w > 0 && s.w < 512 {
+		scale = 512 / s.w
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, s.w*scale, s.h*scale))
+	for y := 0; y < s.h*scale; y++ {
+		for x := 0; x < s.w*scale; x++ {
+			img.SetRGBA(x, y, heat(values[s.at(y/scale, x/scale)]))
+		}
+	}
+	return png.Encode(w, img)
+}
+
+// drawHeatmap renders values as heatmap asks
+func drawHeatmap(s *sightlines, values []float64) error {
+	switch heatmap {
+	case "":
+		return nil
+	case "ansi":
+		renderANSI(os.Stdout, s, values)
+		return nil
+	}
+
+	out, err := os.Create(heatmap)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	return renderPNG(out, s, values)
+}
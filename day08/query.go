@@ -0,0 +1,48 @@
+package day08
+
+import "fmt"
+
+type tree struct {
+	r, c   int
+	height int
+}
+
+var direction_names = []string{"up", "down", "right", "left"}
+
+// lineOfSight lists the trees the tree at r, c can see in each of the directions, nearest first.
+// like the viewing distance, that's every tree up to and including the first one as tall as it
+func lineOfSight(f *forest, r int, c int) ([][]tree, error) {
+	if r < 0 || r >= len(*f) || c < 0 || c >= len((*f)[r]) {
+		return nil, fmt.Errorf("there's no tree at %d,%d", r, c)
+	}
+
+	source_tree_height := (*f)[r][c]
+	seen := make([][]tree, len(directions))
+	for i, d := range directions {
+		seen[i] = []tree{}
+		for x, y := r+d[0], c+d[1]; x >= 0 && y >= 0 && x < len(*f) && y < len((*f)[x]); x, y = x+d[0], y+d[1] {
+			seen[i] = append(seen[i], tree{x, y, (*f)[x][y]})
+			if (*f)[x][y] >= source_tree_height {
+				break
+			}
+		}
+	}
+	return seen, nil
+}
+
+func printLineOfSight(f *forest, r int, c int) error {
+	seen, err := lineOfSight(f, r, c)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Tree %d,%d (height %d) can see:\n", r, c, (*f)[r][c])
+	for i, trees := range seen {
+		fmt.Printf("  %-5s %d:", direction_names[i], len(trees))
+		for _, t := range trees {
+			fmt.Printf(" %d,%d(%d)", t.r, t.c, t.height)
+		}
+		fmt.Println()
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package day08
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+)
+
+// sightlines holds what every tree can see, indexed by r*w+c.  scenic is the product of the
+// viewing distances in all four directions
+type sightlines struct {
+	h, w    int
+	visible []bool
+	scenic  []int
+}
+
+func (s *sightlines) at(r int, c int) int {
+	return r*s.w + c
+}
+
+// analyseForest works out visibility and scenic scores for the whole forest in O(n²), by sweeping
+// along every row and column in both directions.  a sweep keeps a stack of the trees behind it
+// that nothing closer has hidden yet, which is decreasing in height, so each tree is pushed and
+// popped at most once per sweep
+func analyseForest(f *forest) *sightlines {
+	h := len(*f)
+	w := 0
+	if h > 0 {
+		w = len((*f)[0])
+	}
+	s := &sightlines{h: h, w: w, visible: make([]bool, h*w), scenic: make([]int, h*w)}
+	for i := range s.scenic {
+		s.scenic[i] = 1
+	}
+
+	stack := make([]int, 0, h+w)
+	for r := 0; r < h; r++ {
+		stack = s.sweep(f, stack, r, 0, 0, 1, w)
+		stack = s.sweep(f, stack, r, w-1, 0, -1, w)
+	}
+	for c := 0; c < w; c++ {
+		stack = s.sweep(f, stack, 0, c, 1, 0, h)
+		stack = s.sweep(f, stack, h-1, c, -1, 0, h)
+	}
+
+	return s
+}
+
+// sweep walks n trees from (r, c) in steps of (dr, dc), looking back the way it came from each one.
+// the stack is only passed in so its memory can be reused
+func (s *sightlines) sweep(f *forest, stack []int, r int, c int, dr int, dc int, n int) []int {
+	stack = stack[:0]
+	height := func(k int) int {
+		return (*f)[r+k*dr][c+k*dc]
+	}
+
+	for k := 0; k < n; k++ {
+		tree_height := height(k)
+		for len(stack) > 0 && height(stack[len(stack)-1]) < tree_height {
+			stack = stack[:len(stack)-1]
+		}
+
+		i := s.at(r+k*dr, c+k*dc)
+		if len(stack) == 0 {
+			// nothing behind is as tall, so it can see all the way to the edge
+			s.visible[i] = true
+			s.scenic[i] *= k
+		} else {
+			s.scenic[i] *= k - stack[len(stack)-1]
+		}
+		stack = append(stack, k)
+	}
+	return stack
+}
+
+// verifySightlines checks the sweeps against walking out from every tree one at a time, returning
+// a description of each tree they disagree on
+func verifySightlines(f *forest, s *sightlines) []string {
+	mismatches := []string{}
+	for r := 0; r < len(*f); r++ {
+		for c := 0; c < len((*f)[r]); c++ {
+			i := s.at(r, c)
+			if visible := isVisible(f, r, c); visible != s.visible[i] {
+				mismatches = append(mismatches, fmt.Sprintf("tree %d,%d visible: walked %t, swept %t", r, c, visible, s.visible[i]))
+			}
+			if score := calculateScenicScore(f, r, c); score != s.scenic[i] {
+				mismatches = append(mismatches, fmt.Sprintf("tree %d,%d scenic score: walked %d, swept %d", r, c, score, s.scenic[i]))
+			}
+		}
+	}
+	return mismatches
+}
+
+// generate writes a random forest of the given size, with args rows, columns and optionally a seed
+func generate(w io.Writer, args []string) error {
+	if len(args) < 2 || len(args) > 3 {
+		return fmt.Errorf("expected rows, columns and optionally a seed")
+	}
+	numbers := []int{}
+	for _, a := range args {
+		n, err := strconv.Atoi(a)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid number %q", a)
+		}
+		numbers = append(numbers, n)
+	}
+	seed := int64(1)
+	if len(numbers) == 3 {
+		seed = int64(numbers[2])
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	line := make([]byte, numbers[1]+1)
+	line[numbers[1]] = '\n'
+	for r := 0; r < numbers[0]; r++ {
+		for c := 0; c < numbers[1]; c++ {
+			line[c] = byte('0' + rng.Intn(10))
+		}
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
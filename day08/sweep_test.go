@@ -0,0 +1,102 @@
+package day08
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// compareSweeps checks every tree the sweeps worked out against walking out from it one at a time
+func compareSweeps(t *testing.T, f *forest) {
+	t.Helper()
+	s := analyseForest(f)
+	if mismatches := verifySightlines(f, s); len(mismatches) > 0 {
+		t.Errorf("forest %v:\n%s", *f, strings.Join(mismatches, "\n"))
+	}
+}
+
+func TestAnalyseIntro(t *testing.T) {
+	f, err := readForest(strings.NewReader("30373\n25512\n65332\n33549\n35390\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := analyseForest(f)
+
+	visible, best := 0, 0
+	for i := range s.visible {
+		if s.visible[i] {
+			visible++
+		}
+		if s.scenic[i] > best {
+			best = s.scenic[i]
+		}
+	}
+	if visible != 21 || best != 8 {
+		t.Errorf("got %d visible trees and a best scenic score of %d, want 21 and 8", visible, best)
+	}
+	if s.scenic[s.at(3, 2)] != 8 {
+		t.Errorf("tree 3,2 has a scenic score of %d, want 8", s.scenic[s.at(3, 2)])
+	}
+}
+
+func TestSweepsMatchWalking(t *testing.T) {
+	forests := []string{
+		"30373\n25512\n65332\n33549\n35390\n",
+		// a single tree, a single row and a single column are nothing but edges
+		"5\n",
+		"31415\n",
+		"3\n1\n4\n1\n5\n",
+		// equal heights block the view, so only the edges can be seen
+		"555\n555\n555\n",
+		"0000\n0000\n",
+		// a taller tree behind one of the same height as its neighbour
+		"9559\n5555\n9559\n",
+		"12321\n23432\n34543\n23432\n12321\n",
+		"54345\n43234\n32123\n43234\n54345\n",
+	}
+	for _, s := range forests {
+		f, err := readForest(strings.NewReader(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		compareSweeps(t, f)
+	}
+}
+
+func TestSweepsMatchWalkingOnGeneratedForests(t *testing.T) {
+	rng := rand.New(rand.NewSource(8))
+	for round := 0; round < 200; round++ {
+		h, w := 1+rng.Intn(9), 1+rng.Intn(9)
+		// few heights makes equal heights common, which is where the sweeps are easiest to get wrong
+		heights := 1 + rng.Intn(10)
+		f := make(forest, h)
+		for r := range f {
+			f[r] = make([]int, w)
+			for c := range f[r] {
+				f[r][c] = rng.Intn(heights)
+			}
+		}
+		compareSweeps(t, &f)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	sb := strings.Builder{}
+	if err := generate(&sb, []string{"4", "7", "3"}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := readForest(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*f) != 4 || len((*f)[0]) != 7 {
+		t.Errorf("generated a %dx%d forest, want 4x7", len(*f), len((*f)[0]))
+	}
+	compareSweeps(t, f)
+
+	for _, args := range [][]string{{}, {"4"}, {"4", "x"}, {"4", "-1"}, {"1", "2", "3", "4"}} {
+		if err := generate(&strings.Builder{}, args); err == nil {
+			t.Errorf("%q: expected an error", args)
+		}
+	}
+}
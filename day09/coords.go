@@ -0,0 +1,216 @@
+package day09
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"citro.net/advent-2022-go/lib/ints"
+)
+
+// coordinates is the geometry a rope moves through.  the rope only ever asks it how the head
+// moves, whether two knots are touching, and how a knot catches up with the one ahead of it, so
+// the same rope works on any grid where those make sense.  the rest is for drawing, in boxes
+// given by their lowest and highest corners
+type coordinates[P comparable] interface {
+	origin() P
+	move(p P, direction string) (P, bool)
+	touching(a P, b P) bool
+	follow(knot P, ahead P) P
+
+	// extend grows the box from lo to hi to take in p
+	extend(lo P, hi P, p P) (P, P)
+	// around is a box of the given size centred on p
+	around(p P, width int, height int) (P, P)
+	draw(w io.Writer, lo P, hi P, cell func(P) string)
+}
+
+func sign(n int) int {
+	if n > 0 {
+		return 1
+	}
+	if n < 0 {
+		return -1
+	}
+	return 0
+}
+
+type point struct {
+	x int
+	y int
+}
+
+// square is the puzzle's grid, where knots touch diagonally and follow diagonally too
+type square struct{}
+
+var square_steps = map[string]point{
+	"U": {0, 1},
+	"D": {0, -1},
+	"L": {-1, 0},
+	"R": {1, 0},
+}
+
+func (square) origin() point { return point{} }
+
+func (square) move(p point, direction string) (point, bool) {
+	d, ok := square_steps[direction]
+	return point{p.x + d.x, p.y + d.y}, ok
+}
+
+func (square) touching(a point, b point) bool {
+	return ints.Abs(a.x-b.x) <= 1 && ints.Abs(a.y-b.y) <= 1
+}
+
+func (square) follow(knot point, ahead point) point {
+	return point{knot.x + sign(ahead.x-knot.x), knot.y + sign(ahead.y-knot.y)}
+}
+
+func (square) extend(lo point, hi point, p point) (point, point) {
+	return point{ints.Min(lo.x, p.x), ints.Min(lo.y, p.y)}, point{ints.Max(hi.x, p.x), ints.Max(hi.y, p.y)}
+}
+
+func (square) around(p point, width int, height int) (point, point) {
+	lo := point{p.x - width/2, p.y - height/2}
+	return lo, point{lo.x + width - 1, lo.y + height - 1}
+}
+
+// draw puts up at the top, like the puzzle
+func (square) draw(w io.Writer, lo point, hi point, cell func(point) string) {
+	sb := strings.Builder{}
+	for y := hi.y; y >= lo.y; y-- {
+		for x := lo.x; x <= hi.x; x++ {
+			sb.WriteString(cell(point{x, y}))
+		}
+		sb.WriteString("\n")
+	}
+	io.WriteString(w, sb.String())
+}
+
+type point3 struct {
+	x, y, z int
+}
+
+// cube is the square grid with depth, moving forward and back as well.  knots touch if they're
+// in any of the 26 cells around each other, and follow along every axis at once
+type cube struct{}
+
+var cube_steps = map[string]point3{
+	"U": {0, 1, 0},
+	"D": {0, -1, 0},
+	"L": {-1, 0, 0},
+	"R": {1, 0, 0},
+	"F": {0, 0, 1},
+	"B": {0, 0, -1},
+}
+
+func (cube) origin() point3 { return point3{} }
+
+func (cube) move(p point3, direction string) (point3, bool) {
+	d, ok := cube_steps[direction]
+	return point3{p.x + d.x, p.y + d.y, p.z + d.z}, ok
+}
+
+func (cube) touching(a point3, b point3) bool {
+	return ints.Abs(a.x-b.x) <= 1 && ints.Abs(a.y-b.y) <= 1 && ints.Abs(a.z-b.z) <= 1
+}
+
+func (cube) follow(knot point3, ahead point3) point3 {
+	return point3{knot.x + sign(ahead.x-knot.x), knot.y + sign(ahead.y-knot.y), knot.z + sign(ahead.z-knot.z)}
+}
+
+func (cube) extend(lo point3, hi point3, p point3) (point3, point3) {
+	return point3{ints.Min(lo.x, p.x), ints.Min(lo.y, p.y), ints.Min(lo.z, p.z)},
+		point3{ints.Max(hi.x, p.x), ints.Max(hi.y, p.y), ints.Max(hi.z, p.z)}
+}
+
+// around only takes in the slice p is in, since that's what a window onto the head would show
+func (cube) around(p point3, width int, height int) (point3, point3) {
+	lo := point3{p.x - width/2, p.y - height/2, p.z}
+	return lo, point3{lo.x + width - 1, lo.y + height - 1, p.z}
+}
+
+// draw shows the box a slice at a time, from the front back
+func (cube) draw(w io.Writer, lo point3, hi point3, cell func(point3) string) {
+	for z := hi.z; z >= lo.z; z-- {
+		fmt.Fprintf(w, "z=%d\n", z)
+		square{}.draw(w, point{lo.x, lo.y}, point{hi.x, hi.y}, func(p point) string {
+			return cell(point3{p.x, p.y, z})
+		})
+	}
+}
+
+// hexPoint is an axial coordinate.  q runs east and r runs south east, so the third cube
+// coordinate, s, is -q-r
+type hexPoint struct {
+	q, r int
+}
+
+// hex is a grid of pointy topped hexagons.  knots touch if they're next to each other, and a
+// knot that's been left behind takes whichever step brings it closest to the one ahead.  when
+// two steps are as close, which happens when the knot ahead is off to one side, it takes the first
+// of them in hex_directions
+type hex struct{}
+
+var hex_directions = []string{"E", "W", "NE", "NW", "SE", "SW"}
+
+var hex_steps = map[string]hexPoint{
+	"E":  {1, 0},
+	"W":  {-1, 0},
+	"NE": {1, -1},
+	"NW": {0, -1},
+	"SE": {0, 1},
+	"SW": {-1, 1},
+}
+
+func hexDistance(a hexPoint, b hexPoint) int {
+	dq, dr := a.q-b.q, a.r-b.r
+	return ints.Max(ints.Abs(dq), ints.Abs(dr), ints.Abs(dq+dr))
+}
+
+func (hex) origin() hexPoint { return hexPoint{} }
+
+func (hex) move(p hexPoint, direction string) (hexPoint, bool) {
+	d, ok := hex_steps[direction]
+	return hexPoint{p.q + d.q, p.r + d.r}, ok
+}
+
+func (hex) touching(a hexPoint, b hexPoint) bool {
+	return hexDistance(a, b) <= 1
+}
+
+func (h hex) follow(knot hexPoint, ahead hexPoint) hexPoint {
+	best := knot
+	for _, direction := range hex_directions {
+		next, _ := h.move(knot, direction)
+		if hexDistance(next, ahead) < hexDistance(best, ahead) {
+			best = next
+		}
+	}
+	return best
+}
+
+func (hex) extend(lo hexPoint, hi hexPoint, p hexPoint) (hexPoint, hexPoint) {
+	return hexPoint{ints.Min(lo.q, p.q), ints.Min(lo.r, p.r)}, hexPoint{ints.Max(hi.q, p.q), ints.Max(hi.r, p.r)}
+}
+
+func (hex) around(p hexPoint, width int, height int) (hexPoint, hexPoint) {
+	lo := hexPoint{p.q - width/2, p.r - height/2}
+	return lo, hexPoint{lo.q + width - 1, lo.r + height - 1}
+}
+
+// draw shows each row of the box shifted half a cell further right than the one above, so
+// cells line up with their neighbours, and the box comes out as a parallelogram
+func (hex) draw(w io.Writer, lo hexPoint, hi hexPoint, cell func(hexPoint) string) {
+	sb := strings.Builder{}
+	for r := lo.r; r <= hi.r; r++ {
+		sb.WriteString(strings.Repeat(" ", r-lo.r))
+		for q := lo.q; q <= hi.q; q++ {
+			if q > lo.q {
+				sb.WriteString(" ")
+			}
+			sb.WriteString(cell(hexPoint{q, r}))
+		}
+		sb.WriteString("\n")
+	}
+	io.WriteString(w, sb.String())
+}
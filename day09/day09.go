@@ -1,353 +1,115 @@
-package main
+package day09
 
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
-	"strconv"
-	"strings"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
-type point struct {
-	x int
-	y int
-}
+// grid is the coordinates the rope moves through, square as in the puzzle, cube for U, D, L, R,
+// F and B in three dimensions, or hex for E, W, NE, NW, SE and SW on hexagons
+var grid = "square"
 
-type board struct {
-	visited map[point]bool
-	tail    point
-	head    point
-}
+// knot_count overrides how many knots both parts' ropes have, 0 leaves each part its own
+var knot_count = 0
 
-func (b *board) moveHeadOne(direction string) {
-	switch direction {
-	case "U":
-		b.head.y++
-	case "D":
-		b.head.y--
-	case "L":
-		b.head.x--
-	case "R":
-		b.head.x++
+// simulate pulls a rope of the given number of knots through every motion, on the grid the
+// grid param picks, and returns how many positions the tail visited
+func simulate(file io.Reader, knots int) int {
+	if knot_count != 0 {
+		knots = knot_count
 	}
 
-}
-
-func (b *board) headAndTailAreTouching() bool {
-	x_distance := b.head.x - b.tail.x
-	if x_distance < 0 {
-		x_distance = x_distance * -1
-	}
-	y_distance := b.head.y - b.tail.y
-	if y_distance < 0 {
-		y_distance = y_distance * -1
+	var visited int
+	var err error
+	switch grid {
+	case "square":
+		visited, err = pull[point](file, square{}, knots)
+	case "cube":
+		visited, err = pull[point3](file, cube{}, knots)
+	case "hex":
+		visited, err = pull[hexPoint](file, hex{}, knots)
+	default:
+		err = fmt.Errorf("unknown grid %q, expected square, cube or hex", grid)
 	}
-
-	if x_distance == 0 && y_distance <= 1 {
-		return true
+	if err != nil {
+		panic(err)
 	}
-
-	if y_distance == 0 && x_distance <= 1 {
-		return true
-	}
-
-	if x_distance == 1 && y_distance == 1 {
-		return true
-	}
-
-	return false
+	return visited
 }
 
-func (b *board) dragTail() {
-	if b.headAndTailAreTouching() {
-		return
-	}
-
-	y_dir := 0
-	if b.head.y > b.tail.y {
-		y_dir = 1
-	} else if b.head.y < b.tail.y {
-		y_dir = -1
+func pull[P comparable](file io.Reader, coords coordinates[P], knots int) (int, error) {
+	r, err := newRope(coords, knots)
+	if err != nil {
+		return 0, err
 	}
-
-	x_dir := 0
-	if b.head.x > b.tail.x {
-		x_dir = 1
-	} else if b.head.x < b.tail.x {
-		x_dir = -1
+	rd, err := newRenderer[P](os.Stdout)
+	if err != nil {
+		return 0, err
 	}
-
-	b.tail.x += x_dir
-	b.tail.y += y_dir
-}
-
-func (b *board) move(m string) {
-	direction := string(m[0])
-	length, _ := strconv.Atoi(m[2:])
-
-	for i := 0; i < length; i++ {
-		b.moveHeadOne(direction)
-		b.dragTail()
-		b.print()
-		b.visited[b.tail] = true
+	if err := rd.start(r); err != nil {
+		return 0, err
 	}
-}
-
-func (b *board) print() {
-	size := 6
-	println("")
-	for y := size; y >= 0; y-- {
-		for x := 0; x <= size; x++ {
-			if x == b.head.x && y == b.head.y {
-				print("H")
-			} else if x == b.tail.x && y == b.tail.y {
-				print("T")
-			} else if x == 0 && y == 0 {
-				print("s")
-			} else {
-				print(".")
-			}
-		}
-		println("")
-	}
-	println("")
-}
-
-func (b *board) printVisited() {
-	size := 6
-	println("")
-	for y := size; y >= 0; y-- {
-		for x := 0; x <= size; x++ {
-			if x == 0 && y == 0 {
-				print("s")
-			} else if b.visited[point{x, y}] {
-				print("X")
-			} else {
-				print(".")
-			}
-		}
-		println("")
-	}
-	println("")
-
-}
-
-func part1(file *os.File) {
-	board := board{visited: make(map[point]bool), tail: point{0, 0}, head: point{0, 0}}
-
-	println("== Initial State ==")
-	board.print()
 
 	sc := bufio.NewScanner(file)
+	line_no := 0
 	for sc.Scan() {
+		line_no++
 		line := sc.Text()
 		if line == "" {
 			continue
 		}
 
-		fmt.Printf("== %s ==\n", line)
-		board.move(line)
-	}
-
-	board.printVisited()
-
-	visit_count := 0
-	for _, v := range board.visited {
-		if v {
-			visit_count++
+		m, err := parseMotion(line)
+		if err != nil {
+			return 0, fmt.Errorf("line %d: %w", line_no, err)
 		}
-	}
-	println(visit_count)
-}
-
-// start part2
-// part 1 could be generalized to use part2 with a knot_count of 2,
-// but I'm leaving the original solution in place for posterity
-
-type part2board struct {
-	visited map[point]bool
-	knots   []point
-}
-
-func (b *part2board) moveHeadOne(direction string) {
-	switch direction {
-	case "U":
-		b.knots[0].y++
-	case "D":
-		b.knots[0].y--
-	case "L":
-		b.knots[0].x--
-	case "R":
-		b.knots[0].x++
-	}
-}
-
-func knotsAreTouching(a point, b point) bool {
-	x_distance := a.x - b.x
-	if x_distance < 0 {
-		x_distance = x_distance * -1
-	}
-	y_distance := a.y - b.y
-	if y_distance < 0 {
-		y_distance = y_distance * -1
-	}
-
-	if x_distance == 0 && y_distance <= 1 {
-		return true
-	}
-
-	if y_distance == 0 && x_distance <= 1 {
-		return true
-	}
-
-	if x_distance == 1 && y_distance == 1 {
-		return true
-	}
-
-	return false
-}
-
-func (b *part2board) dragTails() {
-	for head_seq := 0; head_seq < len(b.knots)-1; head_seq++ {
-		head := &b.knots[head_seq]
-		tail := &b.knots[head_seq+1]
-
-		if knotsAreTouching(*head, *tail) {
-			continue
-		}
-
-		y_dir := 0
-		if head.y > tail.y {
-			y_dir = 1
-		}
-		if head.y < tail.y {
-			y_dir = -1
-		}
-
-		x_dir := 0
-		if head.x > tail.x {
-			x_dir = 1
-		}
-		if head.x < tail.x {
-			x_dir = -1
-		}
-
-		tail.x += x_dir
-		tail.y += y_dir
-	}
-}
-
-func (b *part2board) move(m string) {
-	direction := string(m[0])
-	length, _ := strconv.Atoi(m[2:])
-
-	for i := 0; i < length; i++ {
-		b.moveHeadOne(direction)
-		b.dragTails()
-		b.print()
-		b.visited[b.knots[len(b.knots)-1]] = true
-	}
-}
-
-func (b *part2board) getKnotNumberAtPoint(p point) int {
-	for i, knot := range b.knots {
-		if knot.x == p.x && knot.y == p.y {
-			return i
-		}
-	}
-	return -1
-}
-
-func (b *part2board) print() {
-	size := 6
-	println("")
-	for y := size; y >= 0; y-- {
-		for x := 0; x <= size; x++ {
-			knot_number := b.getKnotNumberAtPoint(point{x, y})
-			if knot_number == 0 {
-				print("H")
-			} else if knot_number > 0 {
-				print(knot_number)
-			} else if x == 0 && y == 0 {
-				print("s")
-			} else {
-				print(".")
+		for i := 1; i <= m.length; i++ {
+			if err := r.step(m.direction); err != nil {
+				return 0, fmt.Errorf("line %d: %w on a %s grid", line_no, err, grid)
 			}
-		}
-		println("")
-	}
-	println("")
-}
-
-func (b *part2board) printVisited() {
-	size := 6
-	println("")
-	for y := size; y >= 0; y-- {
-		for x := 0; x <= size; x++ {
-			if x == 0 && y == 0 {
-				print("s")
-			} else if b.visited[point{x, y}] {
-				print("X")
-			} else {
-				print(".")
+			if err := rd.stepped(r, m, i); err != nil {
+				return 0, err
 			}
 		}
-		println("")
 	}
-	println("")
-}
-
-func createPart2Board(knot_count int) *part2board {
-	visited := make(map[point]bool)
-	knots := make([]point, knot_count)
-	for i := 0; i < knot_count; i++ {
-		knots[i] = point{0, 0}
+	if err := rd.finish(r); err != nil {
+		return 0, err
 	}
-	board := part2board{visited: visited, knots: knots}
-	return &board
-}
-
-func part2(file *os.File) {
-	board := createPart2Board(10)
-	println("== Initial State ==")
-	board.print()
 
-	sc := bufio.NewScanner(file)
-	for sc.Scan() {
-		line := sc.Text()
-		if line == "" {
-			continue
-		}
-
-		fmt.Printf("== %s ==\n", line)
-		board.move(line)
-	}
-
-	board.printVisited()
-
-	visit_count := 0
-	for _, v := range board.visited {
-		if v {
-			visit_count++
-		}
+	for i, visited := range r.visited {
+		fmt.Printf("knot %s visited %d positions\n", r.label(i), len(visited))
 	}
-	println(visit_count)
+	return len(r.visited[r.tail()]), sc.Err()
+}
 
+func part1(file io.Reader) any {
+	return simulate(file, 2)
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
-	}
+func part2(file io.Reader) any {
+	return simulate(file, 10)
+}
 
-	file, _ := os.Open(filename)
-	defer file.Close()
-	method(file)
+func init() {
+	aoc.Register(aoc.Day{
+		Year:         2022,
+		Number:       9,
+		Title:        "Rope Bridge",
+		Input:        "day09/input.txt",
+		Examples:     []string{"day09/intro.txt", "day09/part2intro.txt"},
+		Parts:        []aoc.Solver{part1, part2},
+		Capabilities: aoc.Visualisable,
+		Params: []aoc.Param{
+			aoc.StringParam("grid", &grid, "coordinates the rope moves through, as square, cube or hex"),
+			aoc.AtLeast(0, aoc.IntParam("knots", &knot_count, "knots in the rope for both parts instead of their own 2 and 10")),
+			aoc.StringParam("render", &render_mode, "draw the rope after every step, every motion, or at the end, as step, motion or end"),
+			aoc.StringParam("viewport", &viewport, "draw a WxH window that follows the head instead of everything so far"),
+			aoc.AtLeast(0, aoc.IntParam("delay", &frame_delay, "milliseconds to show each frame for, redrawing in place")),
+			aoc.Local(aoc.StringParam("frames", &frames_dir, "directory to write each frame to as its own file instead of printing them")),
+		},
+	})
 }
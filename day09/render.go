@@ -0,0 +1,159 @@
+package day09
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// render_mode draws the rope after every "step", after every "motion", or only at the "end".
+// empty draws nothing
+var render_mode = ""
+
+// viewport is a WxH window that follows the head, empty to fit everything drawn so far
+var viewport = ""
+
+// frame_delay is how many milliseconds to show each frame for, redrawing in place, 0 to print them one after another
+var frame_delay = 0
+
+// frames_dir, if set, gets every frame written to its own numbered file instead of printed
+var frames_dir = ""
+
+// extent is the box around the start and every position any knot has visited, which takes in
+// where the knots are now.  step keeps it up to date, so drawing a frame doesn't rescan the visits
+func (r *rope[P]) extent() (P, P) {
+	return r.lo, r.hi
+}
+
+// drawRope draws the box from lo to hi the way the puzzle does.  where knots overlap the one
+// nearest the head is drawn, and the start is only drawn when no knot covers it
+func drawRope[P comparable](w io.Writer, r *rope[P], lo P, hi P) {
+	r.coords.draw(w, lo, hi, func(p P) string {
+		for i, knot := range r.knots {
+			if knot == p {
+				return r.label(i)
+			}
+		}
+		if p == r.coords.origin() {
+			return "s"
+		}
+		return "."
+	})
+}
+
+// drawVisited draws every position the knot has been in as #
+func drawVisited[P comparable](w io.Writer, r *rope[P], knot int, lo P, hi P) {
+	r.coords.draw(w, lo, hi, func(p P) string {
+		if p == r.coords.origin() {
+			return "s"
+		}
+		if r.visited[knot][p] {
+			return "#"
+		}
+		return "."
+	})
+}
+
+// renderer draws the rope as it's pulled around, as render_mode and the other params ask
+type renderer[P comparable] struct {
+	mode          string
+	width, height int
+	delay         time.Duration
+	dir           string
+	frame         int
+	out           io.Writer
+}
+
+func newRenderer[P comparable](out io.Writer) (*renderer[P], error) {
+	rd := &renderer[P]{mode: render_mode, delay: time.Duration(frame_delay) * time.Millisecond, dir: frames_dir, out: out}
+	switch rd.mode {
+	case "", "step", "motion", "end":
+	default:
+		return nil, fmt.Errorf("unknown render mode %q, expected step, motion or end", rd.mode)
+	}
+
+	if viewport != "" {
+		if _, err := fmt.Sscanf(viewport, "%dx%d", &rd.width, &rd.height); err != nil || rd.width < 1 || rd.height < 1 {
+			return nil, fmt.Errorf("invalid viewport %q, expected WxH", viewport)
+		}
+	}
+	if rd.dir != "" {
+		if err := os.MkdirAll(rd.dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+	return rd, nil
+}
+
+// view is the part of the grid to draw, either the viewport centred on the head or everything so far
+func (rd *renderer[P]) view(r *rope[P]) (P, P) {
+	if rd.width == 0 {
+		return r.extent()
+	}
+	return r.coords.around(r.knots[0], rd.width, rd.height)
+}
+
+// draw shows a single frame, under a heading when there is one
+func (rd *renderer[P]) draw(heading string, draw func(w io.Writer)) error {
+	sb := strings.Builder{}
+	if heading != "" {
+		fmt.Fprintf(&sb, "== %s ==\n\n", heading)
+	}
+	draw(&sb)
+	sb.WriteString("\n")
+	rd.frame++
+
+	if rd.dir != "" {
+		return os.WriteFile(filepath.Join(rd.dir, fmt.Sprintf("frame-%05d.txt", rd.frame)), []byte(sb.String()), 0644)
+	}
+	if rd.delay > 0 {
+		// move the cursor home and clear the screen, so the next frame replaces this one
+		io.WriteString(rd.out, "\x1b[H\x1b[2J")
+	}
+	io.WriteString(rd.out, sb.String())
+	time.Sleep(rd.delay)
+	return nil
+}
+
+func (rd *renderer[P]) drawRope(heading string, r *rope[P]) error {
+	lo, hi := rd.view(r)
+	return rd.draw(heading, func(w io.Writer) { drawRope(w, r, lo, hi) })
+}
+
+func (rd *renderer[P]) start(r *rope[P]) error {
+	if rd.mode == "step" || rd.mode == "motion" {
+		return rd.drawRope("Initial State", r)
+	}
+	return nil
+}
+
+// stepped is called after every step of a motion, the first step being 1
+func (rd *renderer[P]) stepped(r *rope[P], m motion, step int) error {
+	switch {
+	case rd.mode == "step" && step == 1:
+		return rd.drawRope(m.String(), r)
+	case rd.mode == "step":
+		return rd.drawRope("", r)
+	case rd.mode == "motion" && step == m.length:
+		return rd.drawRope(m.String(), r)
+	}
+	return nil
+}
+
+// finish draws the rope where it ended up, and then everywhere the tail has been
+func (rd *renderer[P]) finish(r *rope[P]) error {
+	if rd.mode == "" {
+		return nil
+	}
+
+	if rd.mode == "end" {
+		if err := rd.drawRope("Final State", r); err != nil {
+			return err
+		}
+	}
+	lo, hi := r.extent()
+	return rd.draw("Visited by "+r.label(r.tail()), func(w io.Writer) { drawVisited(w, r, r.tail(), lo, hi) })
+}
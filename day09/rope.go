@@ -0,0 +1,90 @@
+package day09
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type motion struct {
+	direction string
+	length    int
+}
+
+func (m motion) String() string {
+	return fmt.Sprintf("%s %d", m.direction, m.length)
+}
+
+// parseMotion reads a direction and a distance.  which directions there are depends on the
+// coordinates, so they're checked as the rope moves
+func parseMotion(line string) (motion, error) {
+	direction, length_text, ok := strings.Cut(line, " ")
+	if !ok || direction == "" {
+		return motion{}, fmt.Errorf("expected a direction and a distance, got %q", line)
+	}
+	length, err := strconv.Atoi(length_text)
+	if err != nil || length < 0 {
+		return motion{}, fmt.Errorf("invalid distance in %q", line)
+	}
+	return motion{direction, length}, nil
+}
+
+// rope is any number of knots, the head first, with every position each knot has been in.
+// lo and hi box in the start and all of those positions, see extent
+type rope[P comparable] struct {
+	coords  coordinates[P]
+	knots   []P
+	visited []map[P]bool
+	lo, hi  P
+}
+
+func newRope[P comparable](coords coordinates[P], knot_count int) (*rope[P], error) {
+	if knot_count < 1 {
+		return nil, fmt.Errorf("a rope needs at least one knot, not %d", knot_count)
+	}
+
+	r := &rope[P]{coords: coords, knots: make([]P, knot_count), visited: make([]map[P]bool, knot_count), lo: coords.origin(), hi: coords.origin()}
+	for i := range r.knots {
+		r.knots[i] = coords.origin()
+		r.visited[i] = map[P]bool{coords.origin(): true}
+	}
+	return r, nil
+}
+
+func (r *rope[P]) tail() int {
+	return len(r.knots) - 1
+}
+
+// step moves the head one place, and each knot after it follows the one in front if they've
+// come apart.  once a knot stays put, none of the ones behind it can move either
+func (r *rope[P]) step(direction string) error {
+	head, ok := r.coords.move(r.knots[0], direction)
+	if !ok {
+		return fmt.Errorf("can't move %s", direction)
+	}
+	r.knots[0] = head
+	r.visited[0][head] = true
+	r.lo, r.hi = r.coords.extend(r.lo, r.hi, head)
+
+	for i := 1; i < len(r.knots); i++ {
+		if r.coords.touching(r.knots[i-1], r.knots[i]) {
+			break
+		}
+		r.knots[i] = r.coords.follow(r.knots[i], r.knots[i-1])
+		r.visited[i][r.knots[i]] = true
+		r.lo, r.hi = r.coords.extend(r.lo, r.hi, r.knots[i])
+	}
+	return nil
+}
+
+// label is how a knot is drawn.  a rope of two has a head and a tail, longer ones number the
+// knots after the head, like the puzzle does
+func (r *rope[P]) label(knot int) string {
+	switch {
+	case knot == 0:
+		return "H"
+	case len(r.knots) == 2:
+		return "T"
+	}
+	return strconv.Itoa(knot)
+}
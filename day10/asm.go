@@ -0,0 +1,166 @@
+package day10
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// operand is either a register, by name, or a constant
+type operand struct {
+	register string
+	value    int
+}
+
+func (o operand) String() string {
+	if o.register != "" {
+		return o.register
+	}
+	return strconv.Itoa(o.value)
+}
+
+// get reads the operand's value, from the register when it names one
+func (o operand) get(m *machine) int {
+	if o.register != "" {
+		return m.registers[o.register]
+	}
+	return o.value
+}
+
+type operandKind int
+
+const (
+	constant operandKind = iota
+	register
+	either
+)
+
+// opcode describes an instruction: what operands it takes, how many cycles it runs for, and what
+// it does once its last cycle is over.  exec returns how far to move on through the program,
+// which is 1 for anything other than a jump
+type opcode struct {
+	name     string
+	operands []operandKind
+	cycles   int
+	exec     func(m *machine, args []operand) int
+}
+
+// instruction_set is every opcode the assembler knows.  adding an instruction is a matter of
+// adding it here
+var instruction_set = map[string]*opcode{}
+
+func defineOpcode(op *opcode) {
+	instruction_set[op.name] = op
+}
+
+func init() {
+	// the two instructions the puzzle's cpu has
+	defineOpcode(&opcode{"noop", nil, 1, func(m *machine, args []operand) int {
+		return 1
+	}})
+	defineOpcode(&opcode{"addx", []operandKind{either}, 2, func(m *machine, args []operand) int {
+		m.registers["X"] += args[0].get(m)
+		return 1
+	}})
+
+	// and a few more, for programs other than the puzzle's.  any name is a register, and they all start at 0 apart from X
+	defineOpcode(&opcode{"set", []operandKind{register, either}, 1, func(m *machine, args []operand) int {
+		m.registers[args[0].register] = args[1].get(m)
+		return 1
+	}})
+	defineOpcode(&opcode{"add", []operandKind{register, either}, 2, func(m *machine, args []operand) int {
+		m.registers[args[0].register] += args[1].get(m)
+		return 1
+	}})
+	defineOpcode(&opcode{"jmp", []operandKind{either}, 1, func(m *machine, args []operand) int {
+		return args[0].get(m)
+	}})
+	defineOpcode(&opcode{"jnz", []operandKind{either, either}, 1, func(m *machine, args []operand) int {
+		if args[0].get(m) != 0 {
+			return args[1].get(m)
+		}
+		return 1
+	}})
+}
+
+type instruction struct {
+	op   *opcode
+	args []operand
+	line int
+}
+
+func (i instruction) String() string {
+	parts := []string{i.op.name}
+	for _, a := range i.args {
+		parts = append(parts, a.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+func parseOperand(field string, kind operandKind) (operand, error) {
+	if n, err := strconv.Atoi(field); err == nil {
+		if kind == register {
+			return operand{}, fmt.Errorf("expected a register, got %s", field)
+		}
+		return operand{value: n}, nil
+	}
+
+	if kind == constant || strings.Trim(field, "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz") != "" {
+		return operand{}, fmt.Errorf("expected a number, got %s", field)
+	}
+	return operand{register: field}, nil
+}
+
+func parseInstruction(line string) (instruction, error) {
+	fields := strings.Fields(line)
+	op, ok := instruction_set[fields[0]]
+	if !ok {
+		return instruction{}, fmt.Errorf("unknown instruction %s", fields[0])
+	}
+	if len(fields)-1 != len(op.operands) {
+		return instruction{}, fmt.Errorf("%s takes %d operands, not %d", op.name, len(op.operands), len(fields)-1)
+	}
+
+	i := instruction{op: op}
+	for n, kind := range op.operands {
+		a, err := parseOperand(fields[n+1], kind)
+		if err != nil {
+			return instruction{}, fmt.Errorf("%s: %w", op.name, err)
+		}
+		i.args = append(i.args, a)
+	}
+	return i, nil
+}
+
+// assemble reads a program, one instruction a line.  blank lines and anything after a # are
+// ignored, and every line that can't be read is reported, not just the first
+func assemble(file io.Reader) ([]instruction, error) {
+	program := []instruction{}
+	errs := []error{}
+
+	sc := bufio.NewScanner(file)
+	line_no := 0
+	for sc.Scan() {
+		line_no++
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		i, err := parseInstruction(line)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", line_no, err))
+			continue
+		}
+		i.line = line_no
+		program = append(program, i)
+	}
+	if err := sc.Err(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return program, errors.Join(errs...)
+}
@@ -0,0 +1,96 @@
+package day10
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAssemble(t *testing.T) {
+	tests := []struct {
+		name    string
+		program string
+		want    string
+		errs    []string
+	}{
+		{"puzzle", "noop\naddx 3\naddx -5\n", "noop|addx 3|addx -5", nil},
+		{"extended", "set a 4\nadd X a\njnz a -2\njmp 1\n", "set a 4|add X a|jnz a -2|jmp 1", nil},
+		{"comments and blanks", "# setup\n\nnoop  # nothing\n   \naddx   2\n", "noop|addx 2", nil},
+		{"every error", "addx\nmul X 2\nset 3 4\naddx 1 2\nnoop\naddx x-1\n", "noop", []string{
+			"line 1: addx takes 1 operands, not 0",
+			"line 2: unknown instruction mul",
+			"line 3: set: expected a register, got 3",
+			"line 4: addx takes 1 operands, not 2",
+			"line 6: addx: expected a number, got x-1",
+		}},
+	}
+
+	for _, test := range tests {
+		program, err := assemble(strings.NewReader(test.program))
+		got := []string{}
+		for _, i := range program {
+			got = append(got, i.String())
+		}
+		if strings.Join(got, "|") != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, strings.Join(got, "|"), test.want)
+		}
+
+		errs := ""
+		if err != nil {
+			errs = err.Error()
+		}
+		if errs != strings.Join(test.errs, "\n") {
+			t.Errorf("%s: got errors\n%s\nwant\n%s", test.name, errs, strings.Join(test.errs, "\n"))
+		}
+	}
+}
+
+func TestMachine(t *testing.T) {
+	tests := []struct {
+		name    string
+		program string
+		// X during each cycle, then the cycles the program took
+		want string
+	}{
+		{"puzzle", "noop\naddx 3\naddx -5\n", "1 1 1 4 4 / 5"},
+		{"register operand", "set a 2\naddx a\naddx a\n", "1 1 1 3 3 / 5"},
+		{"add takes two cycles", "add X 10\nnoop\n", "1 1 11 / 3"},
+		{"jump over", "jmp 2\naddx 100\nnoop\n", "1 1 / 2"},
+		{"loop", "set c 2\nadd c -1\naddx 1\njnz c -2\n", "1 1 1 1 1 2 2 2 2 2 3 / 11"},
+		{"jump out backwards", "noop\njmp -5\n", "1 1 / 2"},
+	}
+
+	for _, test := range tests {
+		program, err := assemble(strings.NewReader(test.program))
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := newMachine(program)
+		xs := []string{}
+		m.everyCycle(func(cycle int, m *machine) {
+			xs = append(xs, fmt.Sprint(m.registers["X"]))
+		})
+		if err := m.run(); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if got := fmt.Sprintf("%s / %d", strings.Join(xs, " "), m.cycle); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestMachineLimit(t *testing.T) {
+	program, err := assemble(strings.NewReader("noop\njmp -1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newMachine(program)
+	m.max_cycles = 100
+	if err := m.run(); err == nil {
+		t.Error("expected an endless program to be stopped")
+	}
+	if m.cycle != 100 {
+		t.Errorf("stopped after %d cycles, want 100", m.cycle)
+	}
+}
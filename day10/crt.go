@@ -0,0 +1,70 @@
+package day10
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+const crt_width = 40
+const crt_height = 6
+
+// framebuffer is every pixel the crt has lit, by row and then column
+type framebuffer [crt_height][crt_width]bool
+
+// pixel is whether the crt lights the pixel it draws during a cycle, which it does if the sprite is over it
+func pixel(cycle int, sprite_pos int) bool {
+	x := (cycle - 1) % crt_width
+	return x >= sprite_pos-1 && x <= sprite_pos+1
+}
+
+func pixelChar(lit bool) byte {
+	if lit {
+		return '#'
+	}
+	return '.'
+}
+
+// attachCRT has the crt draw into a framebuffer as the machine runs
+func attachCRT(m *machine) *framebuffer {
+	fb := &framebuffer{}
+	m.everyCycle(func(cycle int, m *machine) {
+		if cycle > crt_width*crt_height {
+			return
+		}
+		fb[(cycle-1)/crt_width][(cycle-1)%crt_width] = pixel(cycle, m.registers["X"])
+	})
+	return fb
+}
+
+// String draws the framebuffer the way the puzzle does, # for lit pixels and . for dark ones
+func (fb *framebuffer) String() string {
+	sb := strings.Builder{}
+	for _, row := range fb {
+		for _, lit := range row {
+			sb.WriteByte(pixelChar(lit))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// writePNG draws the framebuffer with every pixel scaled up to a square, and a border around it
+func (fb *framebuffer) writePNG(w io.Writer) error {
+	const scale = 10
+	img := image.NewRGBA(image.Rect(0, 0, (crt_width+2)*scale, (crt_height+2)*scale))
+	lit, dark := color.RGBA{0xff, 0xcc, 0x33, 0xff}, color.RGBA{0x0f, 0x0f, 0x23, 0xff}
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			r, c := y/scale-1, x/scale-1
+			if r >= 0 && r < crt_height && c >= 0 && c < crt_width && fb[r][c] {
+				img.SetRGBA(x, y, lit)
+			} else {
+				img.SetRGBA(x, y, dark)
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
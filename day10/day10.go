@@ -1,100 +1,125 @@
-package main
+package day10
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"os"
-	"strconv"
 	"strings"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
-type cpu struct {
-	xreg int
+// 20th cycle and every 40 cycles after that, up to 220
+var key_cycles = []int{20, 60, 100, 140, 180, 220}
 
-	// value during nth cycle
-	xreg_history []int
-}
+// max_cycles stops a program that's still running after this many cycles, 0 for no limit
+var max_cycles = 1000000
 
-func buildCPU() cpu {
-	return cpu{
-		xreg:         1,
-		xreg_history: []int{1},
-	}
-}
+// the debugger runs debug_commands, separated by semicolons, unless debug_script names a file of
+// commands to run instead, or - to read them interactively from stdin
+var debug_commands = "break cycle 20; continue; regs; crt"
+var debug_script = ""
 
-func (c *cpu) execute(instruction string) {
-	if instruction == "" {
-		return
-	}
+// crt_png, if set, is a file to save the image part 2 draws to
+var crt_png = ""
 
-	parts := strings.Split(instruction, " ")
-	opcode := parts[0]
-
-	// noop - 1 cycle, no change
-	if opcode == "noop" {
-		c.xreg_history = append(c.xreg_history, c.xreg)
-		return
+func load(file io.Reader) *machine {
+	program, err := assemble(file)
+	if err != nil {
+		panic(err)
 	}
 
-	// addx - add arg to xreg after two cycles
-	amount, _ := strconv.Atoi(parts[1])
-	c.xreg_history = append(c.xreg_history, c.xreg)
-	c.xreg_history = append(c.xreg_history, c.xreg)
-	c.xreg += amount
+	m := newMachine(program)
+	m.max_cycles = max_cycles
+	return m
 }
 
-func part1(file *os.File) {
-	cpu := buildCPU()
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		cpu.execute(scanner.Text())
-	}
-
-	// 20th cycle and every 40 cycles after that, up to 220
-	key_cycles := []int{20, 60, 100, 140, 180, 220}
+func part1(file io.Reader) any {
+	m := load(file)
 	total_strength := 0
 	for _, v := range key_cycles {
-		fmt.Printf("Cycle %d: %d\n", v, cpu.xreg_history[v])
-		total_strength += cpu.xreg_history[v] * v
+		m.during(v, func(cycle int, m *machine) {
+			fmt.Printf("Cycle %d: %d\n", cycle, m.registers["X"])
+			total_strength += m.registers["X"] * cycle
+		})
 	}
-	println(total_strength)
+
+	if err := m.run(); err != nil {
+		panic(err)
+	}
+	return total_strength
 }
 
-func part2(file *os.File) {
-	cpu := buildCPU()
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		cpu.execute(scanner.Text())
+func part2(file io.Reader) any {
+	m := load(file)
+	fb := attachCRT(m)
+	if err := m.run(); err != nil {
+		panic(err)
 	}
 
-	for y := 0; y < 6; y++ {
-		for x := 1; x <= 40; x++ {
-			cycle := y*40 + x
-			sprit_pos := cpu.xreg_history[cycle]
-			if (x-1) >= sprit_pos-1 && (x-1) <= sprit_pos+1 {
-				print("#")
-			} else {
-				print(".")
-			}
+	fmt.Print(fb)
+	if crt_png != "" {
+		out, err := os.Create(crt_png)
+		if err != nil {
+			panic(err)
+		}
+		defer out.Close()
+		if err := fb.writePNG(out); err != nil {
+			panic(err)
 		}
-		println("")
 	}
 
+	// the answer is the letters the crt draws, but if any of them can't be read, the image is the
+	// next best thing
+	text, err := ocr(fb)
+	if err != nil {
+		fmt.Println(err)
+		return fb.String()
+	}
+	return text
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
+// debug runs the program under the debugger
+func debug(file io.Reader) any {
+	d := newDebugger(load(file), os.Stdout)
+
+	var err error
+	if debug_script == "-" {
+		err = d.runScript(os.Stdin, true)
+	} else if debug_script != "" {
+		var script *os.File
+		if script, err = os.Open(debug_script); err == nil {
+			err = d.runScript(script, false)
+			script.Close()
 		}
+	} else {
+		err = d.runScript(strings.NewReader(strings.ReplaceAll(debug_commands, ";", "\n")), false)
+	}
+
+	if err != nil {
+		panic(err)
 	}
+	return nil
+}
 
-	file, _ := os.Open(filename)
-	defer file.Close()
-	method(file)
+func init() {
+	aoc.Register(aoc.Day{
+		Year:     2022,
+		Number:   10,
+		Title:    "Cathode-Ray Tube",
+		Input:    "day10/input.txt",
+		Examples: []string{"day10/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+		Modes: []aoc.Mode{
+			{Name: "debug", Usage: "a debugger for stepping through the program", Run: debug},
+		},
+		Capabilities: aoc.Visualisable,
+		Params: []aoc.Param{
+			aoc.AtLeast(1, aoc.IntsParam("key-cycles", &key_cycles, "cycles to sum the signal strength of")),
+			aoc.StringParam("commands", &debug_commands, "commands for the debugger, separated by semicolons"),
+			aoc.Local(aoc.StringParam("script", &debug_script, "file of commands for the debugger instead, or - to type them in")),
+			aoc.Local(aoc.StringParam("png", &crt_png, "file to save the image part 2 draws to")),
+			aoc.AtLeast(0, aoc.IntParam("max-cycles", &max_cycles, "give up on a program still running after this many cycles, 0 for no limit")),
+		},
+	})
 }
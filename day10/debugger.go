@@ -0,0 +1,382 @@
+package day10
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// breakpoint stops the program during a cycle.  the ones on cycles and lines stop it on every cycle
+// they match, but the ones on registers only stop it when the condition becomes true, not on every
+// cycle it stays true, or continuing would never get past them
+type breakpoint struct {
+	description string
+	hit         func(d *debugger) bool
+	on_change   bool
+	was_true    bool
+}
+
+// snapshot is what the debugger remembers about each cycle, for the history
+type snapshot struct {
+	cycle     int
+	registers map[string]int
+	line      int
+	text      string
+}
+
+// history_size is how many cycles the debugger remembers.  it has to cover a whole crt row
+const history_size = 256
+
+// history is a ring buffer of the last history_size snapshots, so a long program doesn't make
+// the debugger grow without limit
+type history struct {
+	snapshots []snapshot
+	start     int
+}
+
+func (h *history) add(s snapshot) {
+	if len(h.snapshots) < history_size {
+		h.snapshots = append(h.snapshots, s)
+		return
+	}
+	h.snapshots[h.start] = s
+	h.start = (h.start + 1) % history_size
+}
+
+// last returns up to n of the most recent snapshots, oldest first
+func (h *history) last(n int) []snapshot {
+	if n > len(h.snapshots) {
+		n = len(h.snapshots)
+	}
+	last := make([]snapshot, 0, n)
+	for i := len(h.snapshots) - n; i < len(h.snapshots); i++ {
+		last = append(last, h.snapshots[(h.start+i)%len(h.snapshots)])
+	}
+	return last
+}
+
+// debugger runs a program a cycle at a time, stopping during a cycle, so the registers are what
+// they are while it's running and the instruction hasn't finished yet
+type debugger struct {
+	m           *machine
+	out         io.Writer
+	breakpoints []*breakpoint
+	history     history
+
+	// in_cycle is whether the machine has stopped during a cycle, rather than before the first
+	in_cycle bool
+}
+
+func newDebugger(m *machine, out io.Writer) *debugger {
+	d := &debugger{m: m, out: out}
+	m.everyCycle(func(cycle int, m *machine) {
+		registers := map[string]int{}
+		for name, value := range m.registers {
+			registers[name] = value
+		}
+		i := m.current()
+		d.history.add(snapshot{cycle, registers, i.line, i.String()})
+	})
+	return d
+}
+
+var debuggerHelp = `step [n]              run n cycles, 1 if not given
+next                  run until the next instruction starts
+continue              run until a breakpoint is hit or the program finishes
+break cycle <n>       stop during cycle n
+break line <n>        stop when the instruction on line n starts
+break <reg> <op> <n>  stop when a register's comparison with n becomes true, op is one of == != < <= > >=
+breaks                list the breakpoints
+delete [n]            delete breakpoint n, or all of them
+regs                  the registers during the current cycle
+history [n]           the registers during the last n cycles, 10 if not given, up to 256
+crt                   the sprite and the crt row being drawn during the current cycle
+list [n]              the program around the current instruction, n lines either side
+help                  this message
+exit                  stop debugging`
+
+// errExit stops a script early
+var errExit = fmt.Errorf("exit")
+
+// ordinal writes a number the way the puzzle does, like 20th
+func ordinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+var comparisons = map[string]func(a, b int) bool{
+	"==": func(a, b int) bool { return a == b },
+	"!=": func(a, b int) bool { return a != b },
+	"<":  func(a, b int) bool { return a < b },
+	"<=": func(a, b int) bool { return a <= b },
+	">":  func(a, b int) bool { return a > b },
+	">=": func(a, b int) bool { return a >= b },
+}
+
+func (d *debugger) addBreakpoint(args []string) error {
+	if len(args) == 2 && (args[0] == "cycle" || args[0] == "line") {
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("break: invalid %s %q", args[0], args[1])
+		}
+		b := &breakpoint{description: args[0] + " " + args[1]}
+		if args[0] == "cycle" {
+			b.hit = func(d *debugger) bool { return d.m.cycle == n }
+		} else {
+			b.hit = func(d *debugger) bool { return d.m.elapsed == 1 && d.m.current().line == n }
+		}
+		d.breakpoints = append(d.breakpoints, b)
+		return nil
+	}
+
+	if len(args) == 3 {
+		compare, ok := comparisons[args[1]]
+		n, err := strconv.Atoi(args[2])
+		if !ok || err != nil {
+			return fmt.Errorf("break: can't read the condition %s", strings.Join(args, " "))
+		}
+		register := args[0]
+		d.breakpoints = append(d.breakpoints, &breakpoint{
+			description: strings.Join(args, " "),
+			hit:         func(d *debugger) bool { return compare(d.m.registers[register], n) },
+			on_change:   true,
+		})
+		return nil
+	}
+	return fmt.Errorf("break: expected cycle <n>, line <n> or <reg> <op> <n>")
+}
+
+// errFinished is returned once the program has run off the end
+var errFinished = fmt.Errorf("the program has finished")
+
+// advance runs the machine on to the middle of the next cycle, returning the breakpoints that were hit
+func (d *debugger) advance() ([]*breakpoint, error) {
+	if d.in_cycle {
+		d.m.endCycle()
+		d.in_cycle = false
+	}
+	if d.m.halted() {
+		return nil, errFinished
+	}
+	if err := d.m.startCycle(); err != nil {
+		return nil, err
+	}
+	d.in_cycle = true
+
+	hit := []*breakpoint{}
+	for _, b := range d.breakpoints {
+		now_true := b.hit(d)
+		if now_true && !(b.on_change && b.was_true) {
+			hit = append(hit, b)
+		}
+		b.was_true = now_true
+	}
+	return hit, nil
+}
+
+// runUntil advances until stop says to, a breakpoint is hit or the program finishes
+func (d *debugger) runUntil(stop func() bool) error {
+	for {
+		hit, err := d.advance()
+		if err == errFinished {
+			d.where()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, b := range hit {
+			fmt.Fprintf(d.out, "breakpoint %s\n", b.description)
+		}
+		if len(hit) > 0 || stop() {
+			d.where()
+			return nil
+		}
+	}
+}
+
+func (d *debugger) where() {
+	if d.m.halted() && !d.in_cycle {
+		fmt.Fprintf(d.out, "The program finished after the %s cycle, and register X ended with the value %d.\n", ordinal(d.m.cycle), d.m.registers["X"])
+		return
+	}
+	if !d.in_cycle {
+		fmt.Fprintln(d.out, "before the 1st cycle")
+		return
+	}
+	i := d.m.current()
+	fmt.Fprintf(d.out, "During the %s cycle, register X has the value %d.  line %d: %s\n", ordinal(d.m.cycle), d.m.registers["X"], i.line, i)
+}
+
+// run executes a single command line
+func (d *debugger) run(line string) error {
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		return nil
+	}
+	count := func(fallback int) (int, error) {
+		if len(args) < 2 {
+			return fallback, nil
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("%s: invalid count %q", args[0], args[1])
+		}
+		return n, nil
+	}
+
+	switch args[0] {
+	case "step", "s":
+		n, err := count(1)
+		if err != nil {
+			return err
+		}
+		target := d.m.cycle + n
+		return d.runUntil(func() bool { return d.m.cycle >= target })
+	case "next", "n":
+		return d.runUntil(func() bool { return d.m.elapsed == 1 })
+	case "continue", "c":
+		return d.runUntil(func() bool { return false })
+	case "break", "b":
+		return d.addBreakpoint(args[1:])
+	case "breaks":
+		for i, b := range d.breakpoints {
+			fmt.Fprintf(d.out, "%d: %s\n", i+1, b.description)
+		}
+	case "delete":
+		if len(args) == 1 {
+			d.breakpoints = nil
+			return nil
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 || n > len(d.breakpoints) {
+			return fmt.Errorf("delete: no breakpoint %s", args[1])
+		}
+		d.breakpoints = append(d.breakpoints[:n-1], d.breakpoints[n:]...)
+	case "regs", "r":
+		d.where()
+		d.regs(d.m.registers)
+	case "history":
+		n, err := count(10)
+		if err != nil {
+			return err
+		}
+		d.printHistory(n)
+	case "crt":
+		d.crt()
+	case "list", "l":
+		n, err := count(3)
+		if err != nil {
+			return err
+		}
+		d.list(n)
+	case "help":
+		fmt.Fprintln(d.out, debuggerHelp)
+	case "exit", "quit", "q":
+		return errExit
+	default:
+		return fmt.Errorf("%s: unknown command, try help", args[0])
+	}
+	return nil
+}
+
+// regs prints every register, X first and then the rest by name
+func (d *debugger) regs(registers map[string]int) {
+	names := []string{}
+	for name := range registers {
+		if name != "X" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(d.out, "  X=%d", registers["X"])
+	for _, name := range names {
+		fmt.Fprintf(d.out, " %s=%d", name, registers[name])
+	}
+	fmt.Fprintln(d.out)
+}
+
+func (d *debugger) printHistory(n int) {
+	for _, s := range d.history.last(n) {
+		fmt.Fprintf(d.out, "cycle %4d  line %3d  %-12s", s.cycle, s.line, s.text)
+		d.regs(s.registers)
+	}
+}
+
+// crt shows what the puzzle's walkthrough does, the sprite's position and the crt row so far,
+// with the pixel being drawn during this cycle last
+func (d *debugger) crt() {
+	if !d.in_cycle {
+		fmt.Fprintln(d.out, "the crt only draws while the program is running")
+		return
+	}
+
+	x := d.m.registers["X"]
+	sprite := make([]byte, crt_width)
+	for i := range sprite {
+		sprite[i] = '.'
+		if i >= x-1 && i <= x+1 {
+			sprite[i] = '#'
+		}
+	}
+
+	row := strings.Builder{}
+	for _, s := range d.history.last((d.m.cycle-1)%crt_width + 1) {
+		row.WriteByte(pixelChar(pixel(s.cycle, s.registers["X"])))
+	}
+
+	fmt.Fprintf(d.out, "Sprite position: %s\n", sprite)
+	fmt.Fprintf(d.out, "Current CRT row: %s\n", row.String())
+}
+
+func (d *debugger) list(n int) {
+	for i := d.m.pc - n; i <= d.m.pc+n; i++ {
+		if i < 0 || i >= len(d.m.program) {
+			continue
+		}
+		marker := "  "
+		if i == d.m.pc && d.in_cycle {
+			marker = "=>"
+		}
+		fmt.Fprintf(d.out, "%s %3d  %s\n", marker, d.m.program[i].line, d.m.program[i])
+	}
+}
+
+// runScript runs every line of script.  with a prompt it behaves interactively, reporting errors
+// and carrying on, otherwise the first error stops the script
+func (d *debugger) runScript(script io.Reader, prompt bool) error {
+	sc := bufio.NewScanner(script)
+	for {
+		if prompt {
+			fmt.Fprintf(d.out, "(cycle %d) ", d.m.cycle)
+		}
+		if !sc.Scan() {
+			return sc.Err()
+		}
+
+		err := d.run(sc.Text())
+		if err == errExit {
+			return nil
+		}
+		if err != nil && !prompt {
+			return err
+		}
+		if err != nil {
+			fmt.Fprintln(d.out, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package day10
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var stopPattern = regexp.MustCompile(`(?m)^(?:During the (\d+)\w\w cycle|The program finished)`)
+
+// debugScript runs the commands in a debugger over the program, returning what it printed
+func debugScript(t *testing.T, program string, commands string) string {
+	t.Helper()
+	p, err := assemble(strings.NewReader(program))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := strings.Builder{}
+	d := newDebugger(newMachine(p), &out)
+	if err := d.runScript(strings.NewReader(strings.ReplaceAll(commands, ";", "\n")), false); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestDebuggerStops(t *testing.T) {
+	tests := []struct {
+		name     string
+		program  string
+		commands string
+		want     string
+	}{
+		{"step", "noop\naddx 2\nnoop\n", "step;step 2;step", "1 3 4"},
+		{"next", "noop\naddx 2\nnoop\n", "step;next;next", "1 2 4"},
+		{"break cycle", "noop\nnoop\nnoop\nnoop\n", "break cycle 3;continue;continue", "3 finished"},
+		{"break line", "noop\naddx 1\nnoop\n", "break line 2;continue;continue", "2 finished"},
+		// a line that starts on every cycle stops the program on every one of them
+		{"break line every cycle", "set c 3\njnz c 0\n", "break line 2;continue;continue;continue", "2 3 4"},
+		// a register condition only stops the program when it becomes true
+		{"break register", "noop\nnoop\naddx 1\nnoop\nnoop\n", "break X == 2;continue;continue", "5 finished"},
+		{"break register again", "addx 1\naddx -1\naddx 1\nnoop\n", "break X == 2;continue;continue;continue", "3 7 finished"},
+		{"delete", "noop\nnoop\nnoop\n", "break cycle 2;delete;continue", "finished"},
+	}
+
+	for _, test := range tests {
+		stopped := []string{}
+		for _, m := range stopPattern.FindAllStringSubmatch(debugScript(t, test.program, test.commands), -1) {
+			if m[1] == "" {
+				stopped = append(stopped, "finished")
+			} else {
+				stopped = append(stopped, m[1])
+			}
+		}
+		if got := strings.Join(stopped, " "); got != test.want {
+			t.Errorf("%s: stopped at %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestDebuggerHistory(t *testing.T) {
+	tests := []struct {
+		steps int
+		n     int
+		first int
+		lines int
+	}{
+		{5, 10, 1, 5},
+		{300, 3, 298, 3},
+		{300, 1000, 300 - history_size + 1, history_size},
+	}
+
+	for _, test := range tests {
+		out := debugScript(t, "jmp 0\n", fmt.Sprintf("step %d;history %d", test.steps, test.n))
+		lines := []string{}
+		for _, line := range strings.Split(out, "\n") {
+			if strings.HasPrefix(line, "cycle") {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) != test.lines {
+			t.Errorf("history %d after %d steps: got %d cycles, want %d", test.n, test.steps, len(lines), test.lines)
+			continue
+		}
+		if want := fmt.Sprintf("cycle %4d ", test.first); !strings.HasPrefix(lines[0], want) {
+			t.Errorf("history %d after %d steps: starts with %q, want cycle %d", test.n, test.steps, lines[0], test.first)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package day10
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// letters are four pixels wide with a blank column after each, so eight fit across the crt
+const glyph_width = 4
+const glyph_pitch = 5
+
+// font is every letter that's turned up in the puzzle's block capitals, drawn a row at a time
+var font = map[string]byte{}
+
+func defineGlyph(letter byte, rows ...string) {
+	font[strings.Join(rows, "\n")] = letter
+}
+
+func init() {
+	defineGlyph('A', ".##.", "#..#", "#..#", "####", "#..#", "#..#")
+	defineGlyph('B', "###.", "#..#", "###.", "#..#", "#..#", "###.")
+	defineGlyph('C', ".##.", "#..#", "#...", "#...", "#..#", ".##.")
+	defineGlyph('E', "####", "#...", "###.", "#...", "#...", "####")
+	defineGlyph('F', "####", "#...", "###.", "#...", "#...", "#...")
+	defineGlyph('G', ".##.", "#..#", "#...", "#.##", "#..#", ".###")
+	defineGlyph('H', "#..#", "#..#", "####", "#..#", "#..#", "#..#")
+	defineGlyph('I', ".###", "..#.", "..#.", "..#.", "..#.", ".###")
+	defineGlyph('J', "..##", "...#", "...#", "...#", "#..#", ".##.")
+	defineGlyph('K', "#..#", "#.#.", "##..", "#.#.", "#.#.", "#..#")
+	defineGlyph('L', "#...", "#...", "#...", "#...", "#...", "####")
+	defineGlyph('O', ".##.", "#..#", "#..#", "#..#", "#..#", ".##.")
+	defineGlyph('P', "###.", "#..#", "#..#", "###.", "#...", "#...")
+	defineGlyph('R', "###.", "#..#", "#..#", "###.", "#.#.", "#..#")
+	defineGlyph('S', ".###", "#...", "#...", ".##.", "...#", "###.")
+	defineGlyph('U', "#..#", "#..#", "#..#", "#..#", "#..#", ".##.")
+	defineGlyph('Z', "####", "...#", "..#.", ".#..", "#...", "####")
+}
+
+// glyph is the bitmap of the letter in the given position, as the font has it
+func (fb *framebuffer) glyph(position int) string {
+	rows := make([]string, crt_height)
+	for r := range rows {
+		row := make([]byte, glyph_width)
+		for c := range row {
+			row[c] = pixelChar(fb[r][position*glyph_pitch+c])
+		}
+		rows[r] = string(row)
+	}
+	return strings.Join(rows, "\n")
+}
+
+// ocr reads the letters off the framebuffer.  a letter that isn't in the font comes out as ?, and
+// is reported with its bitmap
+func ocr(fb *framebuffer) (string, error) {
+	text := []byte{}
+	errs := []error{}
+	for position := 0; position*glyph_pitch+glyph_width <= crt_width; position++ {
+		glyph := fb.glyph(position)
+		letter, ok := font[glyph]
+		if !ok {
+			letter = '?'
+			errs = append(errs, fmt.Errorf("unknown letter %d:\n%s", position+1, glyph))
+		}
+		text = append(text, letter)
+	}
+	return string(text), errors.Join(errs...)
+}
@@ -0,0 +1,79 @@
+package day10
+
+import (
+	"strings"
+	"testing"
+)
+
+// drawText lights the framebuffer with the font's letters, the way the crt would draw them
+func drawText(t *testing.T, text string) *framebuffer {
+	t.Helper()
+	glyphs := map[byte]string{}
+	for glyph, letter := range font {
+		glyphs[letter] = glyph
+	}
+
+	fb := &framebuffer{}
+	for position := 0; position < len(text); position++ {
+		glyph, ok := glyphs[text[position]]
+		if !ok {
+			t.Fatalf("no glyph for %q", text[position])
+		}
+		for r, row := range strings.Split(glyph, "\n") {
+			for c := range row {
+				fb[r][position*glyph_pitch+c] = row[c] == '#'
+			}
+		}
+	}
+	return fb
+}
+
+func TestOCR(t *testing.T) {
+	for _, text := range []string{"ABCEFGHI", "JKLOPRSU", "ZZZZZZZZ", "PLEFULPB"} {
+		got, err := ocr(drawText(t, text))
+		if err != nil {
+			t.Errorf("%s: %v", text, err)
+		}
+		if got != text {
+			t.Errorf("got %s, want %s", got, text)
+		}
+	}
+}
+
+func TestOCRUnknownLetters(t *testing.T) {
+	fb := drawText(t, "HELLO")
+	// a blank letter and a stray pixel aren't in the font
+	fb[2][7*glyph_pitch] = true
+
+	got, err := ocr(fb)
+	if got != "HELLO???" {
+		t.Errorf("got %s, want HELLO???", got)
+	}
+	if err == nil {
+		t.Fatal("expected the unknown letters to be reported")
+	}
+	for _, want := range []string{"unknown letter 6:\n....\n....", "unknown letter 8:\n....\n....\n#..."} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in the error, got\n%s", want, err)
+		}
+	}
+}
+
+func TestOCRReadsTheCRT(t *testing.T) {
+	// the start of the intro's program, which draws stripes rather than letters
+	p, err := assemble(strings.NewReader("addx 15\naddx -11\naddx 6\naddx -3\naddx 5\naddx -1\naddx -8\naddx 13\naddx 4\nnoop\naddx -1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newMachine(p)
+	fb := attachCRT(m)
+	if err := m.run(); err != nil {
+		t.Fatal(err)
+	}
+	if row := fb.String()[:21]; row != "##..##..##..##..##..#" {
+		t.Errorf("the crt drew %s, want the intro's stripes", row)
+	}
+	if _, err := ocr(fb); err == nil {
+		t.Error("expected stripes not to read as letters")
+	}
+}
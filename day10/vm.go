@@ -0,0 +1,99 @@
+package day10
+
+import (
+	"fmt"
+)
+
+// hook is called during a cycle, which is after the previous instruction has finished and before
+// the current one has, so the registers are what the puzzle calls their value "during" the cycle
+type hook func(cycle int, m *machine)
+
+// machine runs a program a cycle at a time.  cycles count from 1, and pc is the index of the
+// instruction in the program that's running
+type machine struct {
+	program   []instruction
+	registers map[string]int
+	pc        int
+	cycle     int
+
+	// elapsed is how many cycles the current instruction has run for
+	elapsed int
+	hooks   []hook
+
+	// max_cycles stops programs that never end, 0 for no limit
+	max_cycles int
+}
+
+func newMachine(program []instruction) *machine {
+	return &machine{program: program, registers: map[string]int{"X": 1}}
+}
+
+// everyCycle calls fn during every cycle
+func (m *machine) everyCycle(fn hook) {
+	m.hooks = append(m.hooks, fn)
+}
+
+// during calls fn during the given cycle
+func (m *machine) during(cycle int, fn hook) {
+	m.everyCycle(func(c int, m *machine) {
+		if c == cycle {
+			fn(c, m)
+		}
+	})
+}
+
+func (m *machine) halted() bool {
+	return m.pc < 0 || m.pc >= len(m.program)
+}
+
+// current is the instruction that's running, which is only valid when the machine hasn't halted
+func (m *machine) current() instruction {
+	return m.program[m.pc]
+}
+
+// tick runs a single cycle, calling the hooks during it and finishing the instruction if this is
+// its last cycle
+func (m *machine) tick() error {
+	if err := m.startCycle(); err != nil {
+		return err
+	}
+	m.endCycle()
+	return nil
+}
+
+// startCycle begins the next cycle and calls the hooks.  tick does this and endCycle together,
+// and they're only separate so the debugger can stop in the middle of a cycle
+func (m *machine) startCycle() error {
+	if m.halted() {
+		return fmt.Errorf("the program has finished")
+	}
+	if m.max_cycles > 0 && m.cycle >= m.max_cycles {
+		return fmt.Errorf("still running after %d cycles, at line %d", m.cycle, m.current().line)
+	}
+
+	m.cycle++
+	m.elapsed++
+	for _, h := range m.hooks {
+		h(m.cycle, m)
+	}
+	return nil
+}
+
+// endCycle finishes the instruction if this was its last cycle
+func (m *machine) endCycle() {
+	i := m.current()
+	if m.elapsed >= i.op.cycles {
+		m.pc += i.op.exec(m, i.args)
+		m.elapsed = 0
+	}
+}
+
+// run ticks until the program finishes
+func (m *machine) run() error {
+	for !m.halted() {
+		if err := m.tick(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
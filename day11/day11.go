@@ -1,220 +1,247 @@
-package main
+package day11
 
 import (
-	"bufio"
 	"fmt"
+	"io"
+	"math/big"
 	"os"
-	"strconv"
-	"strings"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
+// monkey is what the notes say about a monkey.  items are the ones it starts with, and the game
+// keeps track of where they go from there
 type monkey struct {
-	id              int
-	inspectCount    int
-	items           []int
-	operationChar   rune
-	operationScalar int
-	divisorTest     int
-	successTarget   int
-	failureTarget   int
+	id            int
+	line          int
+	index         int
+	items         []int
+	operation     expr
+	test          test
+	successTarget *monkey
+	failureTarget *monkey
 }
 
-func readMonkeys(file *os.File) []*monkey {
-	scanner := bufio.NewScanner(file)
-	monkeys := make([]*monkey, 0)
-	seq := -1
-	for scanner.Scan() {
-		line := scanner.Text()
-		if !strings.HasPrefix(line, "Monkey") {
-			continue
-		}
-
-		seq++
-		m := monkey{id: seq, inspectCount: 0}
-		monkeys = append(monkeys, &m)
+var part1Rounds = 20
+var part2Rounds = 10000
 
-		scanner.Scan()
-		line = scanner.Text() // Starting items
-		itemsList := strings.Split(line[18:], ", ")
-		monkeys[seq].items = make([]int, len(itemsList))
-		for i, v := range itemsList {
-			monkeys[seq].items[i], _ = strconv.Atoi(v)
-		}
-
-		scanner.Scan()
-		line = scanner.Text() // Operation
-		monkeys[seq].operationChar = rune(line[23])
-		operationText := line[25:]
-		if operationText == "old" {
-			monkeys[seq].operationScalar = -1
-		} else {
-			monkeys[seq].operationScalar, _ = strconv.Atoi(operationText)
-		}
+// worryMode picks how worry levels are kept in check for both parts, relief, modular or exact.
+// empty leaves part 1 with relief and part 2 with modular, as the puzzle has them
+var worryMode = ""
 
-		scanner.Scan()
-		line = scanner.Text() // Test
-		monkeys[seq].divisorTest, _ = strconv.Atoi(line[21:])
+// reliefDivisor is how much worry drops by when a monkey gets bored with an item under relief
+var reliefDivisor = 3
 
-		scanner.Scan()
-		line = scanner.Text() // If true
-		monkeys[seq].successTarget, _ = strconv.Atoi(line[29:])
+// verifyRounds, when it isn't 0, checks modular worry against exact worry for that many rounds
+// before a part that uses modular worry is played
+var verifyRounds = 0
 
-		scanner.Scan()
-		line = scanner.Text() // If false
-		monkeys[seq].failureTarget, _ = strconv.Atoi(line[30:])
+func mustReadMonkeys(file io.Reader) []*monkey {
+	monkeys, err := readMonkeys(file)
+	if err != nil {
+		panic(err)
 	}
-
 	return monkeys
 }
 
-func (m *monkey) print() {
+func (m *monkey) print(items string) {
 	fmt.Printf("Monkey %d:\n", m.id)
-	fmt.Printf("  Starting items: %v\n", m.items)
+	fmt.Printf("  Items: %s\n", items)
+	fmt.Printf("  Operation: new = %s\n", m.operation)
+	fmt.Printf("  Test: %s\n", m.test)
+	fmt.Printf("    If true: throw to monkey %d\n", m.successTarget.id)
+	fmt.Printf("    If false: throw to monkey %d\n", m.failureTarget.id)
+	println("")
+}
 
-	operationScalarText := strconv.Itoa(m.operationScalar)
-	if m.operationScalar == -1 {
-		operationScalarText = "old"
+// describeOperation says what happened to the worry level the way the puzzle does, for the
+// operations the puzzle has, and shows the working for anything else
+func describeOperation(e expr, v any) string {
+	if b, ok := e.(binary); ok && b.left == (oldValue{}) {
+		switch {
+		case b.op == '*' && b.right == (oldValue{}):
+			return fmt.Sprintf("Worry level is multiplied by itself to %v", v)
+		case b.op == '+' && b.right != (oldValue{}):
+			return fmt.Sprintf("Worry level increases by %s to %v", b.right, v)
+		case b.op == '*':
+			return fmt.Sprintf("Worry level is multiplied by %s to %v", b.right, v)
+		}
 	}
-	fmt.Printf("  Operation: new = old %c %s\n", m.operationChar, operationScalarText)
-
-	fmt.Printf("  Test: divisible by %d\n", m.divisorTest)
-	fmt.Printf("    If true: throw to monkey %d\n", m.successTarget)
-	fmt.Printf("    If false: throw to monkey %d\n", m.failureTarget)
-	println("")
+	return fmt.Sprintf("Worry level becomes %s = %v", e, v)
 }
 
-func runRound(m *monkey, monkeys []*monkey, worryDivisor int) {
-	fmt.Printf("Monkey %d:\n", m.id)
+// game is the monkeys playing keep away with items whose worry levels are W
+type game[W any] struct {
+	monkeys     []*monkey
+	policy      worryPolicy[W]
+	items       [][]W
+	inspections []int
 
-	for len(m.items) > 0 {
-		v := m.items[0]
-		m.items = m.items[1:]
-		fmt.Printf("  Monkey inspects an item with worry level of %d\n", v)
-		m.inspectCount++
+	// trace gets told about every item every monkey inspects, like the puzzle's walkthrough
+	trace io.Writer
+}
 
-		operationScalar := m.operationScalar
-		if operationScalar == -1 {
-			operationScalar = v
+func newGame[W any](monkeys []*monkey, policy worryPolicy[W], trace io.Writer) *game[W] {
+	g := &game[W]{monkeys: monkeys, policy: policy, items: make([][]W, len(monkeys)), inspections: make([]int, len(monkeys)), trace: trace}
+	for i, m := range monkeys {
+		for _, v := range m.items {
+			g.items[i] = append(g.items[i], policy.start(v))
 		}
+	}
+	return g
+}
 
-		if m.operationChar == '+' {
-			v += operationScalar
-			fmt.Printf("    Worry level increases by %d to %d\n", operationScalar, v)
-		} else {
-			v *= operationScalar
-			fmt.Printf("    Worry level is multiplied by %d to %d\n", operationScalar, v)
-		}
+func (g *game[W]) takeTurn(m *monkey) {
+	fmt.Fprintf(g.trace, "Monkey %d:\n", m.id)
 
-		// sorry, I really should have put a mode in for this, but I'm lazy
-		if worryDivisor == 3 {
-			v /= worryDivisor // part 1
-		} else {
-			v %= worryDivisor // part 2
+	for _, v := range g.items[m.index] {
+		fmt.Fprintf(g.trace, "  Monkey inspects an item with worry level of %v\n", v)
+		g.inspections[m.index]++
+
+		v = g.policy.operate(m.operation, v)
+		fmt.Fprintf(g.trace, "    %s\n", describeOperation(m.operation, v))
+
+		var relief string
+		if v, relief = g.policy.relieve(v); relief != "" {
+			fmt.Fprintf(g.trace, "    %s\n", relief)
 		}
-		fmt.Printf("    Monkey gets bored with item. Worry level is divided by %d to %d\n", worryDivisor, v)
 
-		isDivisible := v%m.divisorTest == 0
-		target := -1
-		if isDivisible {
-			fmt.Printf("    Current worry level is divisible by %d\n", m.divisorTest)
+		target := m.failureTarget
+		if g.policy.holds(m.test, v) {
+			fmt.Fprintf(g.trace, "    Current worry level is %s\n", m.test)
 			target = m.successTarget
 		} else {
-			fmt.Printf("    Current worry level is not divisible by %d\n", m.divisorTest)
-			target = m.failureTarget
+			fmt.Fprintf(g.trace, "    Current worry level is not %s\n", m.test)
 		}
 
-		fmt.Printf("    Item with worry level %d is thrown to monkey %d\n", v, target)
-		monkeys[target].items = append(monkeys[target].items, v)
+		fmt.Fprintf(g.trace, "    Item with worry level %v is thrown to monkey %d\n", v, target.id)
+		g.items[target.index] = append(g.items[target.index], v)
 	}
-	println("")
-
+	g.items[m.index] = g.items[m.index][:0]
+	fmt.Fprintln(g.trace)
 }
 
-func part1(file *os.File) {
-	monkeys := readMonkeys(file)
-	roundsRemaining := 20
-	worryDivisor := 3
-
-	for roundsRemaining > 0 {
-		for _, m := range monkeys {
-			runRound(m, monkeys, worryDivisor)
-		}
-		roundsRemaining--
+func (g *game[W]) round() {
+	for _, m := range g.monkeys {
+		g.takeTurn(m)
 	}
-	for _, m := range monkeys {
-		m.print()
+}
+
+// business is the two highest numbers of inspections multiplied together
+func (g *game[W]) business() int {
+	for _, m := range g.monkeys {
+		m.print(fmt.Sprint(g.items[m.index]))
 	}
 
 	inspectPlace1 := 0
 	inspectPlace2 := 0
-	for _, m := range monkeys {
-		fmt.Printf("Monkey %d inspected items %d times\n", m.id, m.inspectCount)
-		if m.inspectCount > inspectPlace1 {
+	for _, m := range g.monkeys {
+		count := g.inspections[m.index]
+		fmt.Printf("Monkey %d inspected items %d times\n", m.id, count)
+		if count > inspectPlace1 {
 			inspectPlace2 = inspectPlace1
-			inspectPlace1 = m.inspectCount
-		} else if m.inspectCount > inspectPlace2 {
-			inspectPlace2 = m.inspectCount
+			inspectPlace1 = count
+		} else if count > inspectPlace2 {
+			inspectPlace2 = count
 		}
 	}
 
 	fmt.Printf("The two monkeys who inspected the most items are %d and %d\n", inspectPlace1, inspectPlace2)
-	monkeyBusiness := inspectPlace1 * inspectPlace2
-	println(monkeyBusiness)
+	return inspectPlace1 * inspectPlace2
 }
 
-func part2(file *os.File) {
-	monkeys := readMonkeys(file)
-	roundsRemaining := 10000
-
-	// uses the chinese remainder theorem to say that modular division
-	// by the shared GCD of the divisors will have a unique remainder that
-	// doesn't change the solution for each individual monkey
-	worryDivisor := 1
-	for _, m := range monkeys {
-		worryDivisor *= m.divisorTest
+func playWith[W any](monkeys []*monkey, policy worryPolicy[W], rounds int) int {
+	g := newGame(monkeys, policy, os.Stdout)
+	for i := 0; i < rounds; i++ {
+		g.round()
 	}
+	return g.business()
+}
+
+// verifyModular plays modular and exact worry side by side, checking after every round that
+// every monkey has inspected as many items as it would have, and that each item it's holding has
+// the same level, modulo the lcm, as it would have
+func verifyModular(monkeys []*monkey, policy modularPolicy, rounds int) error {
+	modular := newGame[int](monkeys, policy, io.Discard)
+	exact := newGame[*big.Int](monkeys, exactPolicy{}, io.Discard)
+	modulus := big.NewInt(int64(policy.modulus))
+
+	for round := 1; round <= rounds; round++ {
+		modular.round()
+		exact.round()
 
-	for roundsRemaining > 0 {
 		for _, m := range monkeys {
-			runRound(m, monkeys, worryDivisor)
+			i := m.index
+			if modular.inspections[i] != exact.inspections[i] {
+				return fmt.Errorf("after round %d monkey %d has inspected %d items with modular worry, but %d with exact worry", round, m.id, modular.inspections[i], exact.inspections[i])
+			}
+			if len(modular.items[i]) != len(exact.items[i]) {
+				return fmt.Errorf("after round %d monkey %d holds %d items with modular worry, but %d with exact worry", round, m.id, len(modular.items[i]), len(exact.items[i]))
+			}
+			for n, v := range exact.items[i] {
+				if new(big.Int).Mod(v, modulus).Int64() != int64(modular.items[i][n]) {
+					return fmt.Errorf("after round %d monkey %d's item %d has worry level %d with modular worry, but %s is %s modulo %d", round, m.id, n+1, modular.items[i][n], v, new(big.Int).Mod(v, modulus), policy.modulus)
+				}
+			}
 		}
-		roundsRemaining--
 	}
-	for _, m := range monkeys {
-		m.print()
+
+	fmt.Printf("Modular worry matches exact worry for the first %d rounds\n", rounds)
+	return nil
+}
+
+// play runs the game with the worry mode asked for, or the part's own if none was
+func play(file io.Reader, mode string, rounds int) int {
+	monkeys := mustReadMonkeys(file)
+	if worryMode != "" {
+		mode = worryMode
 	}
 
-	inspectPlace1 := 0
-	inspectPlace2 := 0
-	for _, m := range monkeys {
-		fmt.Printf("Monkey %d inspected items %d times\n", m.id, m.inspectCount)
-		if m.inspectCount > inspectPlace1 {
-			inspectPlace2 = inspectPlace1
-			inspectPlace1 = m.inspectCount
-		} else if m.inspectCount > inspectPlace2 {
-			inspectPlace2 = m.inspectCount
+	switch mode {
+	case "relief":
+		if reliefDivisor < 1 {
+			panic(fmt.Errorf("relief has to divide by at least 1, not %d", reliefDivisor))
 		}
+		return playWith[int](monkeys, reliefPolicy{reliefDivisor}, rounds)
+	case "modular":
+		policy, err := newModularPolicy(monkeys)
+		if err != nil {
+			panic(err)
+		}
+		if verifyRounds > 0 {
+			if err := verifyModular(monkeys, policy, verifyRounds); err != nil {
+				panic(err)
+			}
+		}
+		return playWith[int](monkeys, policy, rounds)
+	case "exact":
+		return playWith[*big.Int](monkeys, exactPolicy{}, rounds)
 	}
+	panic(fmt.Errorf("unknown worry mode %q, expected relief, modular or exact", mode))
+}
 
-	fmt.Printf("The two monkeys who inspected the most items are %d and %d\n", inspectPlace1, inspectPlace2)
-	monkeyBusiness := inspectPlace1 * inspectPlace2
-	println(monkeyBusiness)
-
+func part1(file io.Reader) any {
+	return play(file, "relief", part1Rounds)
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
-	}
+func part2(file io.Reader) any {
+	return play(file, "modular", part2Rounds)
+}
 
-	file, _ := os.Open(filename)
-	defer file.Close()
-	method(file)
+func init() {
+	aoc.Register(aoc.Day{
+		Year:     2022,
+		Number:   11,
+		Title:    "Monkey in the Middle",
+		Input:    "day11/input.txt",
+		Examples: []string{"day11/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+		Params: []aoc.Param{
+			aoc.AtLeast(0, aoc.IntParam("part1-rounds", &part1Rounds, "rounds to play while worry is divided by 3")),
+			aoc.AtLeast(0, aoc.IntParam("part2-rounds", &part2Rounds, "rounds to play without any relief")),
+			aoc.StringParam("worry", &worryMode, "how worry levels are kept down in both parts instead of their own, as relief, modular or exact"),
+			aoc.AtLeast(1, aoc.IntParam("relief", &reliefDivisor, "what relief divides worry levels by")),
+			aoc.AtLeast(0, aoc.IntParam("verify", &verifyRounds, "rounds to check modular worry against exact worry for before playing with it")),
+		},
+	})
 }
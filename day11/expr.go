@@ -0,0 +1,282 @@
+package day11
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// expr is a monkey's operation, worked out from the old worry level
+type expr interface {
+	eval(old int) int
+	String() string
+}
+
+type constant int
+
+func (c constant) eval(old int) int { return int(c) }
+func (c constant) String() string   { return strconv.Itoa(int(c)) }
+
+type oldValue struct{}
+
+func (oldValue) eval(old int) int { return old }
+func (oldValue) String() string   { return "old" }
+
+type negate struct {
+	operand expr
+}
+
+func (n negate) eval(old int) int { return -n.operand.eval(old) }
+func (n negate) String() string   { return "-" + bracket(n.operand, 3) }
+
+type binary struct {
+	op          byte
+	left, right expr
+}
+
+// precedence is how tightly an operator binds, and anything that isn't an operator binds tightest
+func precedence(e expr) int {
+	if b, ok := e.(binary); ok {
+		if b.op == '+' || b.op == '-' {
+			return 1
+		}
+		return 2
+	}
+	return 3
+}
+
+// bracket writes e, in brackets if it binds looser than min
+func bracket(e expr, min int) string {
+	if precedence(e) < min {
+		return "(" + e.String() + ")"
+	}
+	return e.String()
+}
+
+func (b binary) eval(old int) int {
+	left, right := b.left.eval(old), b.right.eval(old)
+	switch b.op {
+	case '+':
+		return left + right
+	case '-':
+		return left - right
+	case '*':
+		return left * right
+	}
+	if right == 0 {
+		panic(fmt.Errorf("division by zero in %s with old = %d", b, old))
+	}
+	return left / right
+}
+
+// String only brackets what it has to.  the right hand side of - and / needs brackets at the same
+// precedence too, since they don't associate
+func (b binary) String() string {
+	p := precedence(b)
+	rightMin := p
+	if b.op == '-' || b.op == '/' {
+		rightMin = p + 1
+	}
+	return fmt.Sprintf("%s %c %s", bracket(b.left, p), b.op, bracket(b.right, rightMin))
+}
+
+// exprParser is a recursive descent parser over the tokens of an expression
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+func tokenise(s string) ([]string, error) {
+	tokens := []string{}
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		start := i
+		switch {
+		case unicode.IsSpace(c):
+			i++
+			continue
+		case strings.ContainsRune("+-*/()", c):
+			i++
+		case unicode.IsDigit(c):
+			for i < len(s) && unicode.IsDigit(rune(s[i])) {
+				i++
+			}
+		case unicode.IsLetter(c):
+			for i < len(s) && unicode.IsLetter(rune(s[i])) {
+				i++
+			}
+		default:
+			return nil, fmt.Errorf("unexpected %q in %q", c, s)
+		}
+		tokens = append(tokens, s[start:i])
+	}
+	return tokens, nil
+}
+
+// parseExpr reads an expression of old and whole numbers, with + - * /, brackets and negation
+func parseExpr(s string) (expr, error) {
+	tokens, err := tokenise(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens}
+	e, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in %q", p.tokens[p.pos], s)
+	}
+	return e, nil
+}
+
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *exprParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *exprParser) sum() (expr, error) {
+	e, err := p.product()
+	for err == nil && (p.peek() == "+" || p.peek() == "-") {
+		op := p.next()[0]
+		var right expr
+		if right, err = p.product(); err == nil {
+			e = binary{op, e, right}
+		}
+	}
+	return e, err
+}
+
+func (p *exprParser) product() (expr, error) {
+	e, err := p.factor()
+	for err == nil && (p.peek() == "*" || p.peek() == "/") {
+		op := p.next()[0]
+		var right expr
+		if right, err = p.factor(); err == nil {
+			e = binary{op, e, right}
+		}
+	}
+	return e, err
+}
+
+func (p *exprParser) factor() (expr, error) {
+	t := p.next()
+	switch {
+	case t == "":
+		return nil, fmt.Errorf("expression ends too soon")
+	case t == "old":
+		return oldValue{}, nil
+	case t == "-":
+		e, err := p.factor()
+		return negate{e}, err
+	case t == "(":
+		e, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		return e, nil
+	}
+
+	n, err := strconv.Atoi(t)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected %q, expected old, a number or (", t)
+	}
+	return constant(n), nil
+}
+
+// test decides which monkey an item is thrown to
+type test interface {
+	holds(v int) bool
+	String() string
+}
+
+type divisibleBy int
+
+func (d divisibleBy) holds(v int) bool { return v%int(d) == 0 }
+func (d divisibleBy) String() string   { return fmt.Sprintf("divisible by %d", int(d)) }
+
+type comparison struct {
+	op    string
+	value int
+}
+
+// comparisons are the ways a test can compare, and how they read in the notes
+var comparisons = []struct {
+	op, words string
+	holds     func(a, b int) bool
+}{
+	{"==", "equal to", func(a, b int) bool { return a == b }},
+	{"!=", "not equal to", func(a, b int) bool { return a != b }},
+	{">=", "at least", func(a, b int) bool { return a >= b }},
+	{"<=", "at most", func(a, b int) bool { return a <= b }},
+	{">", "greater than", func(a, b int) bool { return a > b }},
+	{"<", "less than", func(a, b int) bool { return a < b }},
+}
+
+func (c comparison) holds(v int) bool {
+	for _, cmp := range comparisons {
+		if cmp.op == c.op {
+			return cmp.holds(v, c.value)
+		}
+	}
+	return false
+}
+
+// holdsCmp is holds for a value that's already been compared with c.value, given as -1, 0 or 1 the
+// way big.Int's Cmp returns it, so values too big for an int can be tested
+func (c comparison) holdsCmp(cmp int) bool {
+	for _, op := range comparisons {
+		if op.op == c.op {
+			return op.holds(cmp, 0)
+		}
+	}
+	return false
+}
+
+func (c comparison) String() string {
+	for _, cmp := range comparisons {
+		if cmp.op == c.op {
+			return fmt.Sprintf("%s %d", cmp.words, c.value)
+		}
+	}
+	return fmt.Sprintf("%s %d", c.op, c.value)
+}
+
+// parseTest reads what follows "Test:", either "divisible by N", a comparison in words like
+// "greater than N", or one with a symbol like "> N"
+func parseTest(s string) (test, error) {
+	s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if n, ok := strings.CutPrefix(s, "divisible by "); ok {
+		d, err := strconv.Atoi(n)
+		if err != nil || d == 0 {
+			return nil, fmt.Errorf("invalid divisor in %q", s)
+		}
+		return divisibleBy(d), nil
+	}
+
+	for _, cmp := range comparisons {
+		for _, prefix := range []string{cmp.words, cmp.op} {
+			if n, ok := strings.CutPrefix(s, prefix); ok {
+				value, err := strconv.Atoi(strings.TrimSpace(n))
+				if err != nil {
+					return nil, fmt.Errorf("invalid number in %q", s)
+				}
+				return comparison{cmp.op, value}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unknown test %q, expected divisible by N or a comparison", s)
+}
@@ -0,0 +1,105 @@
+package day11
+
+import (
+	"testing"
+)
+
+func TestParseExpr(t *testing.T) {
+	tests := []struct {
+		input   string
+		old     int
+		want    int
+		printed string
+		wantErr bool
+	}{
+		{"old * 19", 79, 1501, "old * 19", false},
+		{"old + 6", 54, 60, "old + 6", false},
+		{"old * old", 79, 6241, "old * old", false},
+		{" old+3 ", 1, 4, "old + 3", false},
+		{"1 + 2 * old", 3, 7, "1 + 2 * old", false},
+		{"(1 + 2) * old", 3, 9, "(1 + 2) * old", false},
+		{"old - (2 - 1)", 5, 4, "old - (2 - 1)", false},
+		{"old - 2 - 1", 5, 2, "old - 2 - 1", false},
+		{"old / (4 / 2)", 9, 4, "old / (4 / 2)", false},
+		{"-old + 10", 3, 7, "-old + 10", false},
+		{"-(old + 1)", 3, -4, "-(old + 1)", false},
+		{"old * ", 0, 0, "", true},
+		{"old ^ 2", 0, 0, "", true},
+		{"(old + 1", 0, 0, "", true},
+		{"old old", 0, 0, "", true},
+		{"new", 0, 0, "", true},
+		{"", 0, 0, "", true},
+	}
+
+	for _, test := range tests {
+		e, err := parseExpr(test.input)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %s", test.input, e)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.input, err)
+			continue
+		}
+		if got := e.eval(test.old); got != test.want {
+			t.Errorf("%q with old = %d: got %d, want %d", test.input, test.old, got, test.want)
+		}
+		if got := e.String(); got != test.printed {
+			t.Errorf("%q: printed as %q, want %q", test.input, got, test.printed)
+		}
+
+		// whatever String writes has to read back as the same expression
+		again, err := parseExpr(e.String())
+		if err != nil || again.String() != e.String() || again.eval(test.old) != test.want {
+			t.Errorf("%q: %q doesn't read back the same", test.input, e.String())
+		}
+	}
+}
+
+func TestParseTest(t *testing.T) {
+	tests := []struct {
+		input   string
+		value   int
+		holds   bool
+		printed string
+		wantErr bool
+	}{
+		{"divisible by 23", 46, true, "divisible by 23", false},
+		{"divisible by 23", 47, false, "divisible by 23", false},
+		{"Divisible  By 5", 10, true, "divisible by 5", false},
+		{"greater than 10", 11, true, "greater than 10", false},
+		{"greater than 10", 10, false, "greater than 10", false},
+		{"at least 10", 10, true, "at least 10", false},
+		{"less than -3", -4, true, "less than -3", false},
+		{"> 10", 11, true, "greater than 10", false},
+		{"<= 10", 11, false, "at most 10", false},
+		{"== 7", 7, true, "equal to 7", false},
+		{"!= 7", 7, false, "not equal to 7", false},
+		{"divisible by 0", 0, false, "", true},
+		{"divisible by x", 0, false, "", true},
+		{"greater than", 0, false, "", true},
+		{"odd", 0, false, "", true},
+	}
+
+	for _, test := range tests {
+		got, err := parseTest(test.input)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %s", test.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.input, err)
+			continue
+		}
+		if got.holds(test.value) != test.holds {
+			t.Errorf("%q on %d: got %t, want %t", test.input, test.value, !test.holds, test.holds)
+		}
+		if got.String() != test.printed {
+			t.Errorf("%q: printed as %q, want %q", test.input, got.String(), test.printed)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package day11
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// readMonkeys reads the notes on each monkey.  it doesn't mind about spacing, capitals, blank
+// lines, or the order of the notes about a monkey, and reports everything it can't make sense of,
+// not just the first.  monkeys take their turns in order of their numbers, whatever order their
+// notes are in
+func readMonkeys(file io.Reader) ([]*monkey, error) {
+	monkeys := []*monkey{}
+	errs := []error{}
+	problem := func(line int, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...)))
+	}
+
+	// seen is which notes the current monkey has, and the line each was on
+	seen := map[string]int{}
+	var m *monkey
+	successTargets, failureTargets := map[*monkey]int{}, map[*monkey]int{}
+
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, ":")
+		key = strings.ToLower(strings.Join(strings.Fields(key), " "))
+		value = strings.TrimSpace(value)
+
+		if id, ok := strings.CutPrefix(key, "monkey "); ok {
+			m = &monkey{line: lineNo}
+			var err error
+			if m.id, err = strconv.Atoi(id); err != nil {
+				problem(lineNo, "invalid monkey number %q", id)
+			}
+			monkeys = append(monkeys, m)
+			seen = map[string]int{}
+			continue
+		}
+		if m == nil {
+			problem(lineNo, "%q isn't about any monkey", line)
+			continue
+		}
+		if first, ok := seen[key]; ok {
+			problem(lineNo, "monkey %d already has %q on line %d", m.id, key, first)
+			continue
+		}
+		seen[key] = lineNo
+
+		var err error
+		switch key {
+		case "starting items":
+			m.items, err = parseItems(value)
+		case "operation":
+			rhs := value
+			if lhs, r, ok := strings.Cut(value, "="); ok {
+				if strings.TrimSpace(lhs) != "new" {
+					err = fmt.Errorf("the operation should set new, not %s", strings.TrimSpace(lhs))
+					break
+				}
+				rhs = r
+			}
+			m.operation, err = parseExpr(rhs)
+		case "test":
+			m.test, err = parseTest(value)
+		case "if true":
+			successTargets[m], err = parseTarget(value)
+		case "if false":
+			failureTargets[m], err = parseTarget(value)
+		default:
+			err = fmt.Errorf("unknown note %q", key)
+		}
+		if err != nil {
+			problem(lineNo, "monkey %d: %s", m.id, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, err)
+	}
+
+	sort.SliceStable(monkeys, func(i, j int) bool { return monkeys[i].id < monkeys[j].id })
+	byID := map[int]*monkey{}
+	for i, m := range monkeys {
+		m.index = i
+		if other, ok := byID[m.id]; ok {
+			problem(m.line, "monkey %d already has notes on line %d", m.id, other.line)
+		}
+		byID[m.id] = m
+	}
+
+	for _, m := range monkeys {
+		if m.operation == nil {
+			problem(m.line, "monkey %d has no operation", m.id)
+		}
+		if m.test == nil {
+			problem(m.line, "monkey %d has no test", m.id)
+		}
+
+		var err error
+		if m.successTarget, err = resolveTarget(m, successTargets, byID, "true"); err != nil {
+			problem(m.line, "%s", err)
+		}
+		if m.failureTarget, err = resolveTarget(m, failureTargets, byID, "false"); err != nil {
+			problem(m.line, "%s", err)
+		}
+	}
+
+	return monkeys, errors.Join(errs...)
+}
+
+// parseItems reads worry levels separated by commas, spaces or both
+func parseItems(s string) ([]int, error) {
+	items := []int{}
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		v, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid worry level %q", field)
+		}
+		items = append(items, v)
+	}
+	return items, nil
+}
+
+// parseTarget reads "throw to monkey N", or anything else ending in the monkey's number
+func parseTarget(s string) (int, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("no monkey to throw to")
+	}
+	id, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return 0, fmt.Errorf("expected throw to monkey N, got %q", s)
+	}
+	return id, nil
+}
+
+func resolveTarget(m *monkey, targets map[*monkey]int, byID map[int]*monkey, outcome string) (*monkey, error) {
+	id, ok := targets[m]
+	if !ok {
+		return nil, fmt.Errorf("monkey %d doesn't say where to throw when the test is %s", m.id, outcome)
+	}
+	target, ok := byID[id]
+	if !ok {
+		return nil, fmt.Errorf("monkey %d throws to monkey %d, which has no notes", m.id, id)
+	}
+	if target == m {
+		return nil, fmt.Errorf("monkey %d throws to itself", m.id)
+	}
+	return target, nil
+}
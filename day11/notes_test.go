@@ -0,0 +1,136 @@
+package day11
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const introNotes = `Monkey 0:
+  Starting items: 79, 98
+  Operation: new = old * 19
+  Test: divisible by 23
+    If true: throw to monkey 2
+    If false: throw to monkey 3
+
+Monkey 1:
+  Starting items: 54, 65, 75, 74
+  Operation: new = old + 6
+  Test: divisible by 19
+    If true: throw to monkey 2
+    If false: throw to monkey 0
+
+Monkey 2:
+  Starting items: 79, 60, 97
+  Operation: new = old * old
+  Test: divisible by 13
+    If true: throw to monkey 1
+    If false: throw to monkey 3
+
+Monkey 3:
+  Starting items: 74
+  Operation: new = old + 3
+  Test: divisible by 17
+    If true: throw to monkey 0
+    If false: throw to monkey 1
+`
+
+// describeMonkeys writes monkeys out in a single line each, to compare against
+func describeMonkeys(monkeys []*monkey) string {
+	lines := []string{}
+	for _, m := range monkeys {
+		target := func(m *monkey) string {
+			if m == nil {
+				return "?"
+			}
+			return fmt.Sprint(m.id)
+		}
+		lines = append(lines, fmt.Sprintf("%d %v %s, %s ? %s : %s", m.id, m.items, m.operation, m.test, target(m.successTarget), target(m.failureTarget)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestReadMonkeys(t *testing.T) {
+	intro := strings.Join([]string{
+		"0 [79 98] old * 19, divisible by 23 ? 2 : 3",
+		"1 [54 65 75 74] old + 6, divisible by 19 ? 2 : 0",
+		"2 [79 60 97] old * old, divisible by 13 ? 1 : 3",
+		"3 [74] old + 3, divisible by 17 ? 0 : 1",
+	}, "\n")
+
+	tests := []struct {
+		name  string
+		notes string
+		want  string
+	}{
+		{"intro", introNotes, intro},
+		{"no blank lines", strings.ReplaceAll(introNotes, "\n\n", "\n"), intro},
+		{"crlf", strings.ReplaceAll(introNotes, "\n", "\r\n"), intro},
+		{"spacing and capitals", "monkey 0:\nSTARTING  ITEMS :1 2,3\noperation:old+1\ntest:Greater Than 5\nif true: monkey 1\nIf False: throw to monkey 1\nMonkey 1:\nStarting items:\nOperation: new = old\nTest: divisible by 2\nIf true: throw to monkey 0\nIf false: throw to monkey 0\n",
+			"0 [1 2 3] old + 1, greater than 5 ? 1 : 1\n1 [] old, divisible by 2 ? 0 : 0"},
+		{"notes out of order", "Monkey 1:\nIf false: throw to monkey 0\nTest: divisible by 2\nOperation: new = old * 2\nIf true: throw to monkey 0\nStarting items: 4\nMonkey 0:\nStarting items: 1\nOperation: new = old + 1\nTest: divisible by 3\nIf true: throw to monkey 1\nIf false: throw to monkey 1\n",
+			"0 [1] old + 1, divisible by 3 ? 1 : 1\n1 [4] old * 2, divisible by 2 ? 0 : 0"},
+	}
+
+	for _, test := range tests {
+		monkeys, err := readMonkeys(strings.NewReader(test.notes))
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if got := describeMonkeys(monkeys); got != test.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", test.name, got, test.want)
+		}
+	}
+}
+
+// otherMonkey gives the monkeys in the tests below someone to throw to
+const otherMonkey = "Monkey 1:\nTest: divisible by 2\nOperation: new = old\nIf true: throw to monkey 0\nIf false: throw to monkey 0\n"
+
+func TestReadMonkeysProblems(t *testing.T) {
+	tests := []struct {
+		name     string
+		notes    string
+		problems []string
+	}{
+		{"before any monkey", "Starting items: 1\n", []string{`line 1: "Starting items: 1" isn't about any monkey`}},
+		{"bad number", "Monkey x:\n", []string{
+			`line 1: invalid monkey number "x"`,
+			"line 1: monkey 0 has no operation",
+			"line 1: monkey 0 has no test",
+			"line 1: monkey 0 doesn't say where to throw when the test is true",
+			"line 1: monkey 0 doesn't say where to throw when the test is false",
+		}},
+		{"every problem reported", "Monkey 0:\nStarting items: 1, x\nOperation: new = old ^ 2\nTest: odd\nIf true: throw to monkey 5\nIf false: throw to monkey 0\nColour: brown\n", []string{
+			`line 2: monkey 0: invalid worry level "x"`,
+			`line 3: monkey 0: unexpected '^' in " old ^ 2"`,
+			`line 4: monkey 0: unknown test "odd", expected divisible by N or a comparison`,
+			`line 7: monkey 0: unknown note "colour"`,
+			"line 1: monkey 0 has no operation",
+			"line 1: monkey 0 has no test",
+			"line 1: monkey 0 throws to monkey 5, which has no notes",
+			"line 1: monkey 0 throws to itself",
+		}},
+		{"repeated note", "Monkey 0:\nTest: divisible by 2\nTest: divisible by 3\nOperation: new = old\nIf true: throw to monkey 1\nIf false: throw to monkey 1\n" + otherMonkey, []string{
+			`line 3: monkey 0 already has "test" on line 2`,
+		}},
+		{"repeated monkey", "Monkey 0:\nTest: divisible by 2\nOperation: new = old\nIf true: throw to monkey 1\nIf false: throw to monkey 1\n" + otherMonkey + otherMonkey, []string{
+			"line 11: monkey 1 already has notes on line 6",
+		}},
+		{"sets the wrong thing", "Monkey 0:\nTest: divisible by 2\nOperation: old = old\nIf true: throw to monkey 1\nIf false: throw to monkey 1\n" + otherMonkey, []string{
+			"line 3: monkey 0: the operation should set new, not old",
+			"line 1: monkey 0 has no operation",
+		}},
+	}
+
+	for _, test := range tests {
+		_, err := readMonkeys(strings.NewReader(test.notes))
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if want := strings.Join(test.problems, "\n"); got != want {
+			t.Errorf("%s: got problems\n%s\nwant\n%s", test.name, got, want)
+		}
+	}
+}
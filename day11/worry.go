@@ -0,0 +1,153 @@
+package day11
+
+import (
+	"fmt"
+	"math/big"
+
+	"citro.net/advent-2022-go/lib/ints"
+)
+
+// worryPolicy is how worry levels of type W are worked out and kept in check.  relieve returns a
+// description of what it did for the trace, empty if it did nothing
+type worryPolicy[W any] interface {
+	start(v int) W
+	operate(e expr, old W) W
+	relieve(v W) (W, string)
+	holds(t test, v W) bool
+}
+
+// reliefPolicy is part 1's, where worry drops once a monkey loses interest in an item
+type reliefPolicy struct {
+	divisor int
+}
+
+func (p reliefPolicy) start(v int) int             { return v }
+func (p reliefPolicy) operate(e expr, old int) int { return e.eval(old) }
+func (p reliefPolicy) holds(t test, v int) bool    { return t.holds(v) }
+
+func (p reliefPolicy) relieve(v int) (int, string) {
+	v /= p.divisor
+	return v, fmt.Sprintf("Monkey gets bored with item. Worry level is divided by %d to %d", p.divisor, v)
+}
+
+// modularPolicy is part 2's.  worry levels are kept modulo the lcm of every monkey's divisor,
+// which doesn't change whether any of them divides a level, so the monkeys throw items exactly as
+// they would with the real levels.  that only holds for operations that are +, - and *, and tests
+// that are divisibility, which newModularPolicy checks
+type modularPolicy struct {
+	modulus int
+}
+
+// modular levels get multiplied together, so the modulus has to be small enough that that can't overflow
+const max_modulus = 1 << 31
+
+func newModularPolicy(monkeys []*monkey) (modularPolicy, error) {
+	divisors := []int{}
+	for _, m := range monkeys {
+		d, ok := m.test.(divisibleBy)
+		if !ok {
+			return modularPolicy{}, fmt.Errorf("monkey %d tests %s, but modular worry needs every test to be divisible by", m.id, m.test)
+		}
+		if hasDivision(m.operation) {
+			return modularPolicy{}, fmt.Errorf("monkey %d's operation %s divides, which modular worry can't do", m.id, m.operation)
+		}
+		divisors = append(divisors, ints.Abs(int(d)))
+	}
+
+	p := modularPolicy{modulus: ints.LCM(divisors...)}
+	if p.modulus <= 0 || p.modulus > max_modulus {
+		return modularPolicy{}, fmt.Errorf("the divisors' lcm is too big for modular worry")
+	}
+	return p, nil
+}
+
+func hasDivision(e expr) bool {
+	switch e := e.(type) {
+	case negate:
+		return hasDivision(e.operand)
+	case binary:
+		return e.op == '/' || hasDivision(e.left) || hasDivision(e.right)
+	}
+	return false
+}
+
+// evalMod works out e modulo m, keeping every intermediate value between 0 and m
+func evalMod(e expr, old int, m int) int {
+	mod := func(v int) int { return ((v % m) + m) % m }
+	switch e := e.(type) {
+	case constant:
+		return mod(int(e))
+	case oldValue:
+		return mod(old)
+	case negate:
+		return mod(-evalMod(e.operand, old, m))
+	case binary:
+		left, right := evalMod(e.left, old, m), evalMod(e.right, old, m)
+		switch e.op {
+		case '+':
+			return mod(left + right)
+		case '-':
+			return mod(left - right)
+		case '*':
+			return mod(left * right)
+		}
+	}
+	panic(fmt.Errorf("can't work out %s modulo %d", e, m))
+}
+
+func (p modularPolicy) start(v int) int             { return evalMod(oldValue{}, v, p.modulus) }
+func (p modularPolicy) operate(e expr, old int) int { return evalMod(e, old, p.modulus) }
+func (p modularPolicy) holds(t test, v int) bool    { return t.holds(v) }
+
+func (p modularPolicy) relieve(v int) (int, string) {
+	return v, ""
+}
+
+// exactPolicy is part 2 without any shortcuts, so worry levels grow without limit.  an item that
+// keeps getting squared doubles its digits every time, so it's only practical for a few dozen
+// rounds, but that's enough to check the others
+type exactPolicy struct{}
+
+func evalBig(e expr, old *big.Int) *big.Int {
+	switch e := e.(type) {
+	case constant:
+		return big.NewInt(int64(e))
+	case oldValue:
+		return new(big.Int).Set(old)
+	case negate:
+		return new(big.Int).Neg(evalBig(e.operand, old))
+	case binary:
+		left, right := evalBig(e.left, old), evalBig(e.right, old)
+		switch e.op {
+		case '+':
+			return left.Add(left, right)
+		case '-':
+			return left.Sub(left, right)
+		case '*':
+			return left.Mul(left, right)
+		}
+		if right.Sign() == 0 {
+			panic(fmt.Errorf("division by zero in %s with old = %s", e, old))
+		}
+		// Quo truncates like go's own division does, so it agrees with eval
+		return left.Quo(left, right)
+	}
+	panic(fmt.Errorf("can't work out %s", e))
+}
+
+func (exactPolicy) start(v int) *big.Int                  { return big.NewInt(int64(v)) }
+func (exactPolicy) operate(e expr, old *big.Int) *big.Int { return evalBig(e, old) }
+
+func (exactPolicy) relieve(v *big.Int) (*big.Int, string) {
+	return v, ""
+}
+
+func (exactPolicy) holds(t test, v *big.Int) bool {
+	switch t := t.(type) {
+	case divisibleBy:
+		return new(big.Int).Rem(v, big.NewInt(int64(t))).Sign() == 0
+	case comparison:
+		return t.holdsCmp(v.Cmp(big.NewInt(int64(t.value))))
+	}
+	panic(fmt.Errorf("can't test %s", t))
+}
@@ -0,0 +1,131 @@
+package day11
+
+import (
+	"math"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestExactHoldsBeyondInt(t *testing.T) {
+	huge := new(big.Int).Add(big.NewInt(math.MaxInt64), big.NewInt(1))
+	tests := []struct {
+		test test
+		v    *big.Int
+		want bool
+	}{
+		{comparison{">", math.MaxInt64}, huge, true},
+		{comparison{">=", math.MaxInt64}, huge, true},
+		{comparison{"<", math.MaxInt64}, huge, false},
+		{comparison{"==", math.MaxInt64}, huge, false},
+		{comparison{"!=", math.MaxInt64}, huge, true},
+		{comparison{"==", math.MaxInt64}, big.NewInt(math.MaxInt64), true},
+		{comparison{"<=", -5}, new(big.Int).Neg(huge), true},
+		{comparison{">", 0}, big.NewInt(-1), false},
+		{divisibleBy(2), huge, true},
+		{divisibleBy(7), huge, false},
+		{divisibleBy(-3), big.NewInt(9), true},
+	}
+
+	for _, test := range tests {
+		if got := (exactPolicy{}).holds(test.test, test.v); got != test.want {
+			t.Errorf("%s holds for %s: got %t, want %t", test.test, test.v, got, test.want)
+		}
+	}
+}
+
+func TestEvalModMatchesEval(t *testing.T) {
+	exprs := []string{"old", "old + 6", "old * 19", "old * old", "-old", "3 - old * 2", "(old + 1) * (old - 1)", "-(old * 7) + 100"}
+	moduli := []int{1, 2, 7, 96577}
+
+	for _, s := range exprs {
+		e, err := parseExpr(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range moduli {
+			for old := -20; old <= 20; old++ {
+				want := ((e.eval(old) % m) + m) % m
+				if got := evalMod(e, old, m); got != want {
+					t.Errorf("%s with old = %d modulo %d: got %d, want %d", s, old, m, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestNewModularPolicy(t *testing.T) {
+	monkeys, err := readMonkeys(strings.NewReader(introNotes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy, err := newModularPolicy(monkeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.modulus != 23*19*13*17 {
+		t.Errorf("got modulus %d, want %d", policy.modulus, 23*19*13*17)
+	}
+
+	tests := []struct {
+		name  string
+		notes string
+	}{
+		{"comparison", strings.Replace(introNotes, "divisible by 23", "greater than 23", 1)},
+		{"division", strings.Replace(introNotes, "old * 19", "old / 19", 1)},
+		{"nested division", strings.Replace(introNotes, "old * 19", "(old / 2) * 19", 1)},
+		{"huge lcm", strings.NewReplacer("23", "2147483647", "19", "2147483629").Replace(introNotes)},
+	}
+	for _, test := range tests {
+		monkeys, err := readMonkeys(strings.NewReader(test.notes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := newModularPolicy(monkeys); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestPlayWith(t *testing.T) {
+	// the game traces every throw to stdout
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+	stdout := os.Stdout
+	os.Stdout = devNull
+	defer func() { os.Stdout = stdout }()
+
+	monkeys, err := readMonkeys(strings.NewReader(introNotes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := playWith[int](monkeys, reliefPolicy{3}, 20); got != 10605 {
+		t.Errorf("relief: got %d, want 10605", got)
+	}
+
+	policy, err := newModularPolicy(monkeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := playWith[int](monkeys, policy, 10000); got != 2713310158 {
+		t.Errorf("modular: got %d, want 2713310158", got)
+	}
+	if err := verifyModular(monkeys, policy, 20); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyModularCatchesMismatch(t *testing.T) {
+	monkeys, err := readMonkeys(strings.NewReader(introNotes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a modulus the divisors don't all divide throws items differently from exact worry
+	if err := verifyModular(monkeys, modularPolicy{modulus: 23 * 19 * 13}, 20); err == nil {
+		t.Error("expected a mismatch with a modulus that leaves out a divisor")
+	}
+}
@@ -1,258 +1,119 @@
-package main
+package day12
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"os"
-	"strings"
-)
 
-type path = []int
+	"citro.net/advent-2022-go/aoc"
+	"citro.net/advent-2022-go/lib/grid"
+	"citro.net/advent-2022-go/lib/search"
+)
 
 type heightmap struct {
-	terrain []int
-	maxX    int
-	start   int
-	end     int
-	endX    int
-	endY    int
-}
-
-func coordsToIndex(x, y, maxX int) int {
-	return y*maxX + x
+	terrain *grid.Grid[int]
+	start   grid.Point
+	end     grid.Point
 }
 
-func loadHeightmap(file *os.File) *heightmap {
-	hm := heightmap{maxX: 0}
-	y := 0
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if len(line) > hm.maxX {
-			hm.maxX = len(line)
+func loadHeightmap(file io.Reader) *heightmap {
+	hm := heightmap{}
+	terrain, err := grid.Parse(file, func(p grid.Point, c byte) (int, error) {
+		if c == 'S' {
+			hm.start = p
+			c = 'a'
+		} else if c == 'E' {
+			hm.end = p
+			c = 'z'
 		}
-		for i, v := range line {
-			if v == 'S' {
-				hm.start = coordsToIndex(i, y, hm.maxX)
-				v = 'a'
-			} else if v == 'E' {
-				hm.end = coordsToIndex(i, y, hm.maxX)
-				hm.endX = i
-				hm.endY = y
-				v = 'z'
-			}
-
-			hm.terrain = append(hm.terrain, int(v-'a'))
+		if c < 'a' || c > 'z' {
+			return 0, fmt.Errorf("invalid height %q", c)
 		}
-		y++
+		return int(c - 'a'), nil
+	})
+	if err != nil {
+		panic(err)
 	}
-
+	hm.terrain = terrain
 	return &hm
 }
 
-func reconstructPath(cameFrom map[int]int, current int, origin int) *path {
-	maxLength := len(cameFrom)
-	totalPath := path{current}
-	for {
-		if _, ok := cameFrom[current]; !ok {
-			break
-		}
-		if current == origin {
-			break
-		}
-		current = cameFrom[current]
-		totalPath = append(totalPath, current)
-		if len(totalPath) > maxLength {
-			panic("Path too long")
-		}
-	}
-	return &totalPath
-}
-
-func abs(x int) int {
-	if x < 0 {
-		return -x
-	}
-	return x
-}
-
-func heuristicCostEstimate(p int, hm *heightmap) int {
-	x := p % hm.maxX
-	y := p / hm.maxX
-
-	return abs(x-hm.endX) + abs(y-hm.endY)
-}
-
-func getNeighbors(current int, hm *heightmap) []int {
-	neighbors := []int{}
-	maxX := hm.maxX
-	maxIndex := len(hm.terrain) - 1
-	currentHeight := hm.terrain[current]
-	for i := 0; i < 4; i++ {
-		nextPoint := current
-		if i == 0 {
-			// east
-			nextPoint++
-			if nextPoint%maxX == 0 {
-				continue
-			}
-		} else if i == 1 {
-			// south
-			nextPoint += maxX
-			if nextPoint > maxIndex {
-				continue
-			}
-		} else if i == 2 {
-			// west
-			nextPoint--
-			if nextPoint%maxX == maxX-1 || nextPoint < 0 {
-				continue
-			}
-		} else if i == 3 {
-			// north
-			nextPoint -= maxX
-			if nextPoint < 0 {
-				continue
-			}
-		}
-		nextPointHeight := hm.terrain[nextPoint]
-		heightDifference := nextPointHeight - currentHeight
-		if heightDifference > 1 {
-			continue
+// getNeighbors is the squares next to current that are at most one higher, so they can be climbed to
+func getNeighbors(current grid.Point, hm *heightmap) []grid.Point {
+	neighbors := []grid.Point{}
+	currentHeight := hm.terrain.At(current)
+	for _, next := range hm.terrain.Neighbours(current, grid.Orthogonal) {
+		if hm.terrain.At(next)-currentHeight <= 1 {
+			neighbors = append(neighbors, next)
 		}
-		neighbors = append(neighbors, nextPoint)
 	}
-
 	return neighbors
 }
 
-func getNeighborWeight(current int, neighbor int, hm *heightmap) int {
-	// this is actually worse than a hardcoded value of 1
-	// I assumed that trying to climb up would be better than going down,
-	// but maybe the puzzle is designed to thwart that?
-	// i left it in place because its roughly equivalent to 1
-	currentHeight := hm.terrain[current]
-	neighborHeight := hm.terrain[neighbor]
-	return 2 - (neighborHeight - currentHeight)
-}
-
-func A_Star(start int, goal int, hm *heightmap) *path {
-	inspections := 0
-	openSet := make(map[int]bool)
-	openSet[start] = true
-
-	cameFrom := make(map[int]int)
-	gScore := make(map[int]int)
-	gScore[start] = 0
-
-	fScore := make(map[int]int)
-	fScore[start] = heuristicCostEstimate(start, hm)
-
-	for len(openSet) > 0 {
-		current := -1
-		for k := range openSet {
-			if current == -1 || fScore[k] < fScore[current] {
-				current = k
-			}
-		}
-
-		if current == goal {
-			fmt.Printf("Inspected %d nodes\n", inspections)
-			return reconstructPath(cameFrom, current, start)
-		}
-
-		delete(openSet, current)
-		for _, neighbor := range getNeighbors(current, hm) {
-			inspections++
-			tentative_gScore := gScore[current] + getNeighborWeight(current, neighbor, hm)
-			neighborScore := gScore[neighbor]
-			if neighborScore == 0 {
-				neighborScore = 999999999
-			}
-
-			if tentative_gScore < neighborScore {
-				cameFrom[neighbor] = current
-				gScore[neighbor] = tentative_gScore
-				fScore[neighbor] = gScore[neighbor] + heuristicCostEstimate(neighbor, hm)
-				if !openSet[neighbor] {
-					openSet[neighbor] = true
-				}
-			}
-		}
+// findShortestPath is the fewest steps from any of the starting points to the end.  every step
+// costs the same, so a breadth first search finds it without needing a heuristic
+func findShortestPath(starts []grid.Point, hm *heightmap) []grid.Point {
+	path, ok := search.BFS(starts, func(p grid.Point) []grid.Point {
+		return getNeighbors(p, hm)
+	}, func(p grid.Point) bool {
+		return p == hm.end
+	})
+	if !ok {
+		panic(fmt.Errorf("there's no way to climb to the end at %s", hm.end))
 	}
-
-	return nil
-}
-
-func findShortestPath(hm *heightmap) *path {
-	return A_Star(hm.start, hm.end, hm)
+	return path
 }
 
 func (hm *heightmap) print() {
-	for i := 0; i < len(hm.terrain); i++ {
-		if i == hm.start {
-			print("S")
-		} else if i == hm.end {
-			print("E")
-		} else {
-			print(string(rune(hm.terrain[i] + 'a')))
+	hm.terrain.Render(os.Stderr, func(p grid.Point, height int) byte {
+		if p == hm.start {
+			return 'S'
+		} else if p == hm.end {
+			return 'E'
 		}
-
-		if i%hm.maxX == hm.maxX-1 {
-			println()
-		}
-	}
+		return byte(height + 'a')
+	})
 	println()
 }
 
-func part1(file *os.File) {
+func part1(file io.Reader) any {
 	hm := loadHeightmap(file)
 	hm.print()
 
-	path := findShortestPath(hm)
-	fmt.Printf("Found path with %d steps\n", len(*path)-1)
+	path := findShortestPath([]grid.Point{hm.start}, hm)
+	fmt.Printf("Found path with %d steps\n", len(path)-1)
 	println()
+	return len(path) - 1
 }
 
-func part2(file *os.File) {
+func part2(file io.Reader) any {
 	hm := loadHeightmap(file)
 	hm.print()
 
-	path := findShortestPath(hm)
-	fewestSteps := len(*path) - 1
-	fmt.Printf("Initial starting point required %d steps\n", fewestSteps)
-
-	// you could probably reverse the search, and search from the "end" point to any point with a height of 0
-	// but the A* search is fast enough to just check every point with a height of 0 as a starting point
-
-	for i := 0; i < len(hm.terrain); i++ {
-		if hm.terrain[i] == 0 {
-			hm.start = i
-			path = findShortestPath(hm)
-			if path != nil && len(*path)-1 < fewestSteps {
-				fewestSteps = len(*path) - 1
-				fmt.Printf("New starting point required %d steps\n", fewestSteps)
+	// searching from every square at height 0 at once finds whichever of them is closest to the end
+	starts := []grid.Point{}
+	for y := 0; y < hm.terrain.H; y++ {
+		for x := 0; x < hm.terrain.W; x++ {
+			if hm.terrain.At(grid.Point{X: x, Y: y}) == 0 {
+				starts = append(starts, grid.Point{X: x, Y: y})
 			}
 		}
 	}
 
-	fmt.Printf("Overall fewest steps: %d\n", fewestSteps)
+	path := findShortestPath(starts, hm)
+	fewestSteps := len(path) - 1
+	fmt.Printf("Starting from %s requires the fewest steps: %d\n", path[0], fewestSteps)
+	return fewestSteps
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
-	}
-
-	file, _ := os.Open(filename)
-	defer file.Close()
-	method(file)
+func init() {
+	aoc.Register(aoc.Day{
+		Year:         2022,
+		Number:       12,
+		Title:        "Hill Climbing Algorithm",
+		Input:        "day12/input.txt",
+		Examples:     []string{"day12/intro.txt"},
+		Parts:        []aoc.Solver{part1, part2},
+		Capabilities: aoc.Visualisable,
+	})
 }
@@ -1,11 +1,12 @@
-package main
+package day13
 
 import (
 	"bufio"
 	"encoding/json"
 	"fmt"
-	"os"
-	"strings"
+	"io"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
 // a packet is a list of values, each of which is either a number or a packet
@@ -28,7 +29,7 @@ func parsePacket(line string) packets {
 	return p
 }
 
-func parseFileToPart1Puzzle(file *os.File) part1Puzzle {
+func parseFileToPart1Puzzle(file io.Reader) part1Puzzle {
 	var puzzle part1Puzzle
 
 	scanner := bufio.NewScanner(file)
@@ -135,7 +136,7 @@ func (p *part1Puzzle) print() {
 	}
 }
 
-func part1(file *os.File) {
+func part1(file io.Reader) any {
 	puzzle := parseFileToPart1Puzzle(file)
 
 	sum := 0
@@ -147,14 +148,14 @@ func part1(file *os.File) {
 			sum += seq
 		}
 	}
-	println(sum)
+	return sum
 }
 
 type part2Puzzle struct {
 	packets packets
 }
 
-func parseFileToPart2Puzzle(file *os.File) part2Puzzle {
+func parseFileToPart2Puzzle(file io.Reader) part2Puzzle {
 	var puzzle part2Puzzle
 
 	scanner := bufio.NewScanner(file)
@@ -196,7 +197,7 @@ func sortPackets(p packets) []packetValue {
 	return sorted
 }
 
-func part2(file *os.File) {
+func part2(file io.Reader) any {
 	puzzle := parseFileToPart2Puzzle(file)
 
 	dividerPacketsJson := []string{
@@ -224,22 +225,16 @@ func part2(file *os.File) {
 		}
 	}
 
-	println(dividerPacket0Index * dividerPacket1Index)
+	return dividerPacket0Index * dividerPacket1Index
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
-	}
-
-	file, _ := os.Open(filename)
-	defer file.Close()
-	method(file)
+func init() {
+	aoc.Register(aoc.Day{
+		Year:     2022,
+		Number:   13,
+		Title:    "Distress Signal",
+		Input:    "day13/input.txt",
+		Examples: []string{"day13/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+	})
 }
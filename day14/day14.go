@@ -1,11 +1,13 @@
-package main
+package day14
 
 import (
 	"bufio"
 	"fmt"
-	"os"
+	"io"
 	"strconv"
 	"strings"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
 const AIR = 0
@@ -38,7 +40,7 @@ func parseLine(line string) rockPath {
 	return rockPath
 }
 
-func readBoard(file *os.File, hasFloor bool) board {
+func readBoard(file io.Reader, hasFloor bool) board {
 	sourceX := 500
 	// first, extract the rock paths from the file
 	scanner := bufio.NewScanner(file)
@@ -213,7 +215,7 @@ func addSand(b *board) bool {
 	}
 }
 
-func part1(file *os.File) {
+func part1(file io.Reader) any {
 	board := readBoard(file, false)
 
 	sandCount := 0
@@ -228,10 +230,10 @@ func part1(file *os.File) {
 	}
 	board.print()
 
-	println(sandCount)
+	return sandCount
 }
 
-func part2(file *os.File) {
+func part2(file io.Reader) any {
 	board := readBoard(file, true)
 
 	sandCount := 0
@@ -246,22 +248,17 @@ func part2(file *os.File) {
 	}
 	board.print()
 
-	println(sandCount)
+	return sandCount
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
-	}
-
-	file, _ := os.Open(filename)
-	defer file.Close()
-	method(file)
+func init() {
+	aoc.Register(aoc.Day{
+		Year:         2022,
+		Number:       14,
+		Title:        "Regolith Reservoir",
+		Input:        "day14/input.txt",
+		Examples:     []string{"day14/intro.txt"},
+		Parts:        []aoc.Solver{part1, part2},
+		Capabilities: aoc.Visualisable,
+	})
 }
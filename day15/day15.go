@@ -1,11 +1,13 @@
-package main
+package day15
 
 import (
 	"bufio"
 	"fmt"
-	"os"
-	"strings"
+	"io"
 	"time"
+
+	"citro.net/advent-2022-go/aoc"
+	"citro.net/advent-2022-go/lib/ints"
 )
 
 type SensorData struct {
@@ -16,7 +18,11 @@ type SensorData struct {
 	sensorRange int
 }
 
-func readSensorData(file *os.File) *[]SensorData {
+// the example uses row 10 and a search range of 20
+var row = 2000000
+var searchRange = 4000000
+
+func readSensorData(file io.Reader) *[]SensorData {
 	var sensorData []SensorData
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
@@ -30,8 +36,8 @@ func readSensorData(file *os.File) *[]SensorData {
 		beaconX := 0
 		beaconY := 0
 		fmt.Sscanf(line, "Sensor at x=%d, y=%d: closest beacon is at x=%d, y=%d", &x, &y, &beaconX, &beaconY)
-		xDistance := abs(x - beaconX)
-		yDistance := abs(y - beaconY)
+		xDistance := ints.Abs(x - beaconX)
+		yDistance := ints.Abs(y - beaconY)
 		sensorRange := xDistance + yDistance
 
 		sensorData = append(sensorData, SensorData{x, y, beaconX, beaconY, sensorRange})
@@ -40,13 +46,6 @@ func readSensorData(file *os.File) *[]SensorData {
 	return &sensorData
 }
 
-func abs(x int) int {
-	if x < 0 {
-		return -x
-	}
-	return x
-}
-
 func isBeaconAt(x, y int, sensorData *[]SensorData) bool {
 	// this could be stored in a map for faster lookup, but the array isn't large so this works fine
 	for _, v := range *sensorData {
@@ -64,8 +63,8 @@ func coordsCanHoldBeacon(x, y int, sensorData *[]SensorData) bool {
 	}
 
 	for _, v := range *sensorData {
-		xDistanceToSensor := abs(v.x - x)
-		yDistanceToSensor := abs(v.y - y)
+		xDistanceToSensor := ints.Abs(v.x - x)
+		yDistanceToSensor := ints.Abs(v.y - y)
 		distanceToSensor := xDistanceToSensor + yDistanceToSensor
 		if distanceToSensor <= v.sensorRange {
 			return false
@@ -75,7 +74,7 @@ func coordsCanHoldBeacon(x, y int, sensorData *[]SensorData) bool {
 	return true
 }
 
-func part1(file *os.File) {
+func part1(file io.Reader) any {
 	sensorData := readSensorData(file)
 	minX := 99999
 	maxX := -99999
@@ -92,7 +91,6 @@ func part1(file *os.File) {
 	}
 	fmt.Printf("minX: %d, maxX: %d\n", minX, maxX)
 
-	row := 2000000
 	blockedPosCount := 0
 	for i := minX; i <= maxX; i++ {
 		if !coordsCanHoldBeacon(i, row, sensorData) {
@@ -100,13 +98,11 @@ func part1(file *os.File) {
 		}
 	}
 
-	println(blockedPosCount)
+	return blockedPosCount
 }
 
-func part2(file *os.File) {
+func part2(file io.Reader) any {
 	sensorData := readSensorData(file)
-	searchRange := 4000000
-	// searchRange = 20
 	var blockingSensorData *SensorData
 
 	lastTs := time.Now()
@@ -124,7 +120,7 @@ func part2(file *os.File) {
 			blockingSensorData = nil
 
 			for _, v := range *sensorData {
-				if (abs(v.x-x) + abs(v.y-y)) <= v.sensorRange {
+				if (ints.Abs(v.x-x) + ints.Abs(v.y-y)) <= v.sensorRange {
 					blockingSensorData = &v
 					break
 				}
@@ -134,7 +130,7 @@ func part2(file *os.File) {
 				// we have just entered a diamond/triangle that is blocked by a sensor
 				// figure out the furthest Y value of that shape along this X row
 
-				xDistanceToSensor := abs(blockingSensorData.x - x)
+				xDistanceToSensor := ints.Abs(blockingSensorData.x - x)
 
 				// manhattan distance to the sensor means that if we know how far the x distance is,
 				// we can figure out the y reach of the sensor
@@ -153,23 +149,24 @@ func part2(file *os.File) {
 
 			tuningFrequency := 4000000*x + y
 			fmt.Printf("Found a spot at x=%d, y=%d, tuning frequency: %d\n", x, y, tuningFrequency)
+			return tuningFrequency
 		}
 	}
-}
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
-	}
+	return nil
+}
 
-	file, _ := os.Open(filename)
-	defer file.Close()
-	method(file)
+func init() {
+	aoc.Register(aoc.Day{
+		Year:     2022,
+		Number:   15,
+		Title:    "Beacon Exclusion Zone",
+		Input:    "day15/input.txt",
+		Examples: []string{"day15/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+		Params: []aoc.Param{
+			aoc.IntParam("row", &row, "row to count positions that cannot contain a beacon"),
+			aoc.AtLeast(0, aoc.IntParam("search-range", &searchRange, "max x and y coordinate the distress beacon can be at")),
+		},
+	})
 }
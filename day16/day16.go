@@ -1,10 +1,12 @@
-package main
+package day16
 
 import (
 	"bufio"
 	"fmt"
-	"os"
+	"io"
 	"strings"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
 type Node struct {
@@ -21,10 +23,13 @@ type Route struct {
 }
 
 var graph Graph
+var start = "AA"
+var part1Duration = 30
+var part2Duration = 26
 var distances map[string]map[string]int
 var usefulValves []string
 
-func readPuzzleGraph(file *os.File) {
+func readPuzzleGraph(file io.Reader) {
 	graph = Graph{}
 	usefulValves = []string{}
 
@@ -105,16 +110,14 @@ func searchRoutes(start string, time int, route Route, visited map[string]bool)
 	return routes
 }
 
-func part1(file *os.File) {
+func part1(file io.Reader) any {
 	readPuzzleGraph(file)
-	start := "AA"
-	duration := 30
 
 	initialRoute := Route{flow: 0, nodes: []string{start}}
 	floydWarshall()
 	visited := make(map[string]bool)
 
-	routes := searchRoutes(start, duration, initialRoute, visited)
+	routes := searchRoutes(start, part1Duration, initialRoute, visited)
 	bestRoute := routes[0]
 	for _, route := range routes {
 		if route.flow > bestRoute.flow {
@@ -123,12 +126,13 @@ func part1(file *os.File) {
 	}
 
 	fmt.Printf("Best route: %v\n", bestRoute)
+	return bestRoute.flow
 }
 
 func allDifferentNodes(nodes1 []string, nodes2 []string) bool {
 	for _, node1 := range nodes1 {
 		for _, node2 := range nodes2 {
-			if node1 == node2 && node1 != "AA" {
+			if node1 == node2 && node1 != start {
 				return false
 			}
 		}
@@ -136,16 +140,14 @@ func allDifferentNodes(nodes1 []string, nodes2 []string) bool {
 	return true
 }
 
-func part2(file *os.File) {
+func part2(file io.Reader) any {
 	readPuzzleGraph(file)
-	start := "AA"
-	duration := 26
 
 	initialRoute := Route{flow: 0, nodes: []string{start}}
 	floydWarshall()
 
 	visited := make(map[string]bool)
-	routes := searchRoutes(start, duration, initialRoute, visited)
+	routes := searchRoutes(start, part2Duration, initialRoute, visited)
 
 	max := 0
 	for _, myRoute := range routes {
@@ -164,21 +166,21 @@ func part2(file *os.File) {
 	}
 
 	fmt.Printf("Max flow: %d\n", max)
+	return max
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
-	}
-
-	file, _ := os.Open(filename)
-	defer file.Close()
-	method(file)
+func init() {
+	aoc.Register(aoc.Day{
+		Year:     2022,
+		Number:   16,
+		Title:    "Proboscidea Volcanium",
+		Input:    "day16/input.txt",
+		Examples: []string{"day16/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+		Params: []aoc.Param{
+			aoc.StringParam("start", &start, "valve to start at"),
+			aoc.AtLeast(0, aoc.IntParam("part1-minutes", &part1Duration, "minutes before the volcano erupts when working alone")),
+			aoc.AtLeast(0, aoc.IntParam("part2-minutes", &part2Duration, "minutes left after teaching the elephant")),
+		},
+	})
 }
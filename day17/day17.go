@@ -1,11 +1,12 @@
-package main
+package day17
 
 import (
 	"bufio"
 	"fmt"
-	"os"
-	"strings"
+	"io"
 	"time"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
 var jetPattern []int
@@ -47,13 +48,17 @@ type Chamber struct {
 }
 
 var chamber Chamber
+var part1RockCount = 2022
+var part2RockCount = 1000000000000
 
 const CHAMBER_WIDTH = 7
+// ARRAY_CAPACITY is how many rows the chamber starts with.  it grows as the tower does, since the
+// rock counts are params
 const ARRAY_CAPACITY = 10000
 const ROCK_START_BOT_BUFFER = 3
 const ROCK_START_LEFT_BUFFER = 2
 
-func loadPuzzle(file *os.File) {
+func loadPuzzle(file io.Reader) {
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -93,6 +98,9 @@ func isValidPosition(shape Shape, x int, y int) bool {
 		if x+v[0] < 0 || x+v[0] >= CHAMBER_WIDTH {
 			return false
 		}
+		if y+v[1] >= len(chamber.rocks) {
+			continue
+		}
 		if chamber.rocks[y+v[1]][x+v[0]] {
 			return false
 		}
@@ -102,6 +110,9 @@ func isValidPosition(shape Shape, x int, y int) bool {
 
 func placeShape(shape Shape, x int, y int) {
 	for _, v := range shape {
+		for y+v[1] >= len(chamber.rocks) {
+			chamber.rocks = append(chamber.rocks, make([]bool, CHAMBER_WIDTH))
+		}
 		chamber.rocks[y+v[1]][x+v[0]] = true
 	}
 }
@@ -117,8 +128,8 @@ func (c *Chamber) print() {
 
 	for y := maxHeight; y >= 0; y-- {
 		print("|")
-		for x := 0; x < len(c.rocks[y]); x++ {
-			if c.rocks[y][x] {
+		for x := 0; x < CHAMBER_WIDTH; x++ {
+			if y < len(c.rocks) && c.rocks[y][x] {
 				print("#")
 			} else {
 				print(".")
@@ -127,7 +138,7 @@ func (c *Chamber) print() {
 		println("|")
 	}
 	print("+")
-	for i := 0; i < len(c.rocks[0]); i++ {
+	for i := 0; i < CHAMBER_WIDTH; i++ {
 		print("-")
 	}
 	println("+")
@@ -145,7 +156,7 @@ type ComboState struct {
 	highestSettledPoint int
 }
 
-func doSimulation(totalRockCount int) {
+func doSimulation(totalRockCount int) int {
 	reportingInterval := 200
 	if totalRockCount > 10000 {
 		reportingInterval = 10000000
@@ -227,32 +238,32 @@ func doSimulation(totalRockCount int) {
 		}
 	}
 
-	println(chamber.highestSettledPoint + cycleHeightAdded)
 	fmt.Printf("Completed in %f milliseconds\n", time.Since(startTime).Seconds()*1000)
+	return chamber.highestSettledPoint + cycleHeightAdded
 }
 
-func part1() {
-	doSimulation(2022)
+func part1(file io.Reader) any {
+	loadPuzzle(file)
+	return doSimulation(part1RockCount)
 }
 
-func part2() {
-	doSimulation(1000000000000)
+func part2(file io.Reader) any {
+	loadPuzzle(file)
+	return doSimulation(part2RockCount)
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
-	}
-
-	file, _ := os.Open(filename)
-	defer file.Close()
-	loadPuzzle(file)
-	method()
+func init() {
+	aoc.Register(aoc.Day{
+		Year:         2022,
+		Number:       17,
+		Title:        "Pyroclastic Flow",
+		Input:        "day17/input.txt",
+		Examples:     []string{"day17/intro.txt"},
+		Parts:        []aoc.Solver{part1, part2},
+		Capabilities: aoc.Visualisable,
+		Params: []aoc.Param{
+			aoc.AtLeast(0, aoc.IntParam("part1-rocks", &part1RockCount, "rocks to drop before measuring the tower")),
+			aoc.AtLeast(0, aoc.IntParam("part2-rocks", &part2RockCount, "rocks to drop before measuring the tower, using cycle detection")),
+		},
+	})
 }
@@ -1,17 +1,19 @@
-package main
+package day18
 
 import (
 	"bufio"
 	"fmt"
-	"os"
-	"strings"
+	"io"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
 const MAX_LEN = 20
 
 var lavaDroplet [MAX_LEN][MAX_LEN][MAX_LEN]bool
 
-func loadPuzzle(file *os.File) {
+func loadPuzzle(file io.Reader) {
+	lavaDroplet = [MAX_LEN][MAX_LEN][MAX_LEN]bool{}
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -28,7 +30,34 @@ func loadPuzzle(file *os.File) {
 	}
 }
 
-func part1() {
+// the droplet is stored in a fixed size grid, so every cube has to fit inside it
+func validatePuzzle(file io.Reader, part int) error {
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		x := 0
+		y := 0
+		z := 0
+
+		if _, err := fmt.Sscanf(line, "%d,%d,%d", &x, &y, &z); err != nil {
+			return fmt.Errorf("cube %q: %w", line, err)
+		}
+		for _, v := range []int{x, y, z} {
+			if v < 0 || v >= MAX_LEN {
+				return fmt.Errorf("cube %s is outside the %dx%dx%d grid", line, MAX_LEN, MAX_LEN, MAX_LEN)
+			}
+		}
+	}
+
+	return nil
+}
+
+func part1(file io.Reader) any {
+	loadPuzzle(file)
 	exposedSides := 0
 	for x := 0; x < MAX_LEN; x++ {
 		for y := 0; y < MAX_LEN; y++ {
@@ -59,10 +88,11 @@ func part1() {
 		}
 	}
 
-	println(exposedSides)
+	return exposedSides
 }
 
-func part2() {
+func part2(file io.Reader) any {
+	loadPuzzle(file)
 	dirs := [6][3]int{
 		{-1, 0, 0},
 		{1, 0, 0},
@@ -142,23 +172,17 @@ func part2() {
 		}
 	}
 
-	println(exteriorSides)
+	return exteriorSides
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
-	}
-
-	file, _ := os.Open(filename)
-	defer file.Close()
-	loadPuzzle(file)
-	method()
+func init() {
+	aoc.Register(aoc.Day{
+		Year:     2022,
+		Number:   18,
+		Title:    "Boiling Boulders",
+		Input:    "day18/input.txt",
+		Examples: []string{"day18/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+		Validate: validatePuzzle,
+	})
 }
@@ -1,12 +1,13 @@
-package main
+package day19
 
 import (
 	"bufio"
 	"fmt"
-	"os"
-	"strings"
+	"io"
 	"time"
 
+	"citro.net/advent-2022-go/aoc"
+
 	"golang.org/x/exp/maps"
 )
 
@@ -37,12 +38,16 @@ type Blueprint struct {
 }
 
 var blueprints []Blueprint
+var part1TimeAlloted = 24
+var part2TimeAlloted = 32
+var part2BlueprintCount = 3
 
 var cacheHit int
 var cacheMiss int
 var stateBestResultCache map[State]int
 
-func loadPuzzle(file *os.File) {
+func loadPuzzle(file io.Reader) {
+	blueprints = []Blueprint{}
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -222,9 +227,9 @@ func (blueprint *Blueprint) print() {
 		blueprint.id, blueprint.oreOreCost, blueprint.clayOreCost, blueprint.obsidianOreCost, blueprint.obsidianClayCost, blueprint.geodeOreCost, blueprint.geodeObsidianCost)
 }
 
-func part1() {
+func part1(file io.Reader) any {
+	loadPuzzle(file)
 	start := time.Now()
-	timeAlloted := 24
 	totalQuality := 0
 	stateBestResultCache = make(map[State]int)
 
@@ -235,21 +240,24 @@ func part1() {
 		maps.Clear(stateBestResultCache)
 		blueprint.print()
 
-		initialState := State{timeRemaining: timeAlloted, oreBots: 1}
+		initialState := State{timeRemaining: part1TimeAlloted, oreBots: 1}
 		maxGeodes := calculateMaxGeodes(&initialState, &blueprint)
 		fmt.Printf("Blueprint %d: Max geodes: %d. Cache hit: %d, miss: %d\n", blueprint.id, maxGeodes, cacheHit, cacheMiss)
 		fmt.Printf("Blueprint time: %s\n", time.Since(blueprintStart))
 		totalQuality += maxGeodes * blueprint.id
 	}
 
-	println(totalQuality)
 	fmt.Printf("Time: %s\n", time.Since(start))
+	return totalQuality
 }
 
-func part2() {
+func part2(file io.Reader) any {
+	loadPuzzle(file)
 	start := time.Now()
-	timeAlloted := 32
-	blueprints = blueprints[0:3]
+	// the example only has two blueprints, so don't slice past the end
+	if part2BlueprintCount < len(blueprints) {
+		blueprints = blueprints[0:part2BlueprintCount]
+	}
 	outputProduct := 1
 	stateBestResultCache = make(map[State]int)
 
@@ -260,31 +268,29 @@ func part2() {
 		maps.Clear(stateBestResultCache)
 		blueprint.print()
 
-		initialState := State{timeRemaining: timeAlloted, oreBots: 1}
+		initialState := State{timeRemaining: part2TimeAlloted, oreBots: 1}
 		maxGeodes := calculateMaxGeodes(&initialState, &blueprint)
 		fmt.Printf("Blueprint %d: Max geodes: %d. Cache hit: %d, miss: %d\n", blueprint.id, maxGeodes, cacheHit, cacheMiss)
 		fmt.Printf("Blueprint time: %s\n", time.Since(blueprintStart))
 		outputProduct *= maxGeodes
 	}
 
-	println(outputProduct)
 	fmt.Printf("Time: %s\n", time.Since(start))
+	return outputProduct
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
-	}
-
-	file, _ := os.Open(filename)
-	defer file.Close()
-	loadPuzzle(file)
-	method()
+func init() {
+	aoc.Register(aoc.Day{
+		Year:     2022,
+		Number:   19,
+		Title:    "Not Enough Minerals",
+		Input:    "day19/input.txt",
+		Examples: []string{"day19/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+		Params: []aoc.Param{
+			aoc.AtLeast(0, aoc.IntParam("part1-minutes", &part1TimeAlloted, "minutes to open geodes with every blueprint")),
+			aoc.AtLeast(0, aoc.IntParam("part2-minutes", &part2TimeAlloted, "minutes to open geodes once the elephants have eaten")),
+			aoc.AtLeast(0, aoc.IntParam("part2-blueprints", &part2BlueprintCount, "how many blueprints survived the elephants")),
+		},
+	})
 }
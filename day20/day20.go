@@ -1,11 +1,12 @@
-package main
+package day20
 
 import (
 	"bufio"
 	"fmt"
-	"os"
+	"io"
 	"strconv"
-	"strings"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
 type Node struct {
@@ -90,7 +91,7 @@ func (l *CyclicDoubleLinkedList) shiftNodeRight(node *Node) {
 	twoAfter.prev = node
 }
 
-func (l *CyclicDoubleLinkedList) printAnswer() {
+func (l *CyclicDoubleLinkedList) printAnswer() int {
 	l.print()
 
 	current := l.head
@@ -119,6 +120,7 @@ func (l *CyclicDoubleLinkedList) printAnswer() {
 	sum := plus1kval + plus2kval + plus3kval
 
 	fmt.Printf("plus1kval: %d, plus2kval: %d, plus3kval: %d, sum: %d\n", plus1kval, plus2kval, plus3kval, sum)
+	return sum
 }
 
 func (l *CyclicDoubleLinkedList) mix() {
@@ -143,7 +145,7 @@ func (l *CyclicDoubleLinkedList) mix() {
 
 var puzzleFile CyclicDoubleLinkedList
 
-func loadPuzzle(file *os.File) {
+func loadPuzzle(file io.Reader) {
 	scanner := bufio.NewScanner(file)
 	puzzleFile = CyclicDoubleLinkedList{}
 	for scanner.Scan() {
@@ -157,14 +159,16 @@ func loadPuzzle(file *os.File) {
 	}
 }
 
-func part1() {
+func part1(file io.Reader) any {
+	loadPuzzle(file)
 	println("Initial arrangement:")
 	puzzleFile.print()
 	puzzleFile.mix()
-	puzzleFile.printAnswer()
+	return puzzleFile.printAnswer()
 }
 
-func part2() {
+func part2(file io.Reader) any {
+	loadPuzzle(file)
 	current := puzzleFile.head
 	for {
 		current.data *= 811589153
@@ -179,23 +183,16 @@ func part2() {
 	for i := 0; i < 10; i++ {
 		puzzleFile.mix()
 	}
-	puzzleFile.printAnswer()
+	return puzzleFile.printAnswer()
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
-	}
-
-	file, _ := os.Open(filename)
-	defer file.Close()
-	loadPuzzle(file)
-	method()
+func init() {
+	aoc.Register(aoc.Day{
+		Year:     2022,
+		Number:   20,
+		Title:    "Grove Positioning System",
+		Input:    "day20/input.txt",
+		Examples: []string{"day20/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+	})
 }
@@ -1,11 +1,13 @@
-package main
+package day21
 
 import (
 	"bufio"
 	"fmt"
-	"os"
+	"io"
 	"strconv"
 	"strings"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
 type MonkeyPlan interface{}
@@ -19,7 +21,7 @@ type MonkeyPlanMath struct {
 
 var monkeyPlans map[string]MonkeyPlan
 
-func loadPuzzle(file *os.File) {
+func loadPuzzle(file io.Reader) {
 	monkeyPlans = make(map[string]MonkeyPlan)
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
@@ -77,9 +79,10 @@ func evaluateFrom(id string) int {
 	}
 }
 
-func part1() {
+func part1(file io.Reader) any {
+	loadPuzzle(file)
 	val := evaluateFrom("root")
-	println(val)
+	return val
 }
 
 func planInvolvesHuman(id string) bool {
@@ -130,7 +133,8 @@ func getExpression(id string) string {
 	}
 }
 
-func part2() {
+func part2(file io.Reader) any {
+	loadPuzzle(file)
 	rootMonkeyPlan := monkeyPlans["root"].(MonkeyPlanMath)
 
 	humanTree := ""
@@ -150,24 +154,18 @@ func part2() {
 	fmt.Printf("%d=%s\n", monkeyTreeValue, textDescription)
 
 	// the next step is to solve the equation above for x
-	// i used an external tool to do this
+	// i used an external tool to do this, so the equation is the answer
 	// @todo consider implementing this in go, as an equation solver or some sort of tree rebalancer?
+	return fmt.Sprintf("%d=%s", monkeyTreeValue, textDescription)
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
-	}
-
-	file, _ := os.Open(filename)
-	defer file.Close()
-	loadPuzzle(file)
-	method()
+func init() {
+	aoc.Register(aoc.Day{
+		Year:     2022,
+		Number:   21,
+		Title:    "Monkey Math",
+		Input:    "day21/input.txt",
+		Examples: []string{"day21/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+	})
 }
@@ -1,11 +1,14 @@
-package main
+package day22
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
-	"os"
+	"io"
 	"strconv"
 	"strings"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
 const BLOCK_VOID = 0
@@ -47,7 +50,11 @@ var path Path
 var state PuzzleState
 var lastFacing map[Pos]int
 
-func loadPuzzle(file *os.File) {
+func loadPuzzle(input io.Reader) {
+	// the input is scanned twice, so buffer it to allow seeking back to the start
+	data, _ := io.ReadAll(input)
+	file := bytes.NewReader(data)
+	startX = -1
 	maxWidth := -1
 	height := 0
 
@@ -125,6 +132,59 @@ func loadPuzzle(file *os.File) {
 	}
 }
 
+// the cube wrapping for part 2 assumes the map is cut into six faces of this size
+const CUBE_FACE_SIZE = 50
+
+// validatePuzzle checks the map is a cube net part 2 can fold.  part 1 only wraps flat, so it
+// takes any map
+func validatePuzzle(file io.Reader, part int) error {
+	if part != 2 {
+		return nil
+	}
+
+	rows := []string{}
+	width := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+		rows = append(rows, line)
+		if len(line) > width {
+			width = len(line)
+		}
+	}
+
+	// walk the map face by face, where every face sized block has to be entirely void or entirely tiles
+	faces := 0
+	for top := 0; top < len(rows); top += CUBE_FACE_SIZE {
+		for left := 0; left < width; left += CUBE_FACE_SIZE {
+			tiles := 0
+			for y := top; y < top+CUBE_FACE_SIZE && y < len(rows); y++ {
+				for x := left; x < left+CUBE_FACE_SIZE && x < len(rows[y]); x++ {
+					if rows[y][x] != ' ' {
+						tiles++
+					}
+				}
+			}
+
+			if tiles == 0 {
+				continue
+			}
+			if tiles != CUBE_FACE_SIZE*CUBE_FACE_SIZE {
+				return fmt.Errorf("the map at row %d, col %d is not a complete %dx%d cube face", top+1, left+1, CUBE_FACE_SIZE, CUBE_FACE_SIZE)
+			}
+			faces++
+		}
+	}
+
+	if faces != 6 {
+		return fmt.Errorf("the map has %d faces of %dx%d tiles, but a cube needs 6", faces, CUBE_FACE_SIZE, CUBE_FACE_SIZE)
+	}
+	return nil
+}
+
 func printPuzzleState() {
 	fmt.Printf("Located at %d,%d facing %d on step %d\n", state.x, state.y, state.dir, state.step)
 	for y := 0; y < len(board); y++ {
@@ -245,7 +305,8 @@ func applyPathNode(node PathNode, useCubeRap bool) {
 	state.step++
 }
 
-func part1() {
+func part1(file io.Reader) any {
+	loadPuzzle(file)
 	state = PuzzleState{startX, startY, startDir, 0}
 	lastFacing[Pos{startX, startY}] = startDir
 	// printPuzzleState()
@@ -261,10 +322,11 @@ func part1() {
 
 	password := 1000*row + 4*col + facing
 	fmt.Printf("Ended at row=%d, col=%d, facing=%d, password=%d\n", row, col, facing, password)
-	println(password)
+	return password
 }
 
-func part2() {
+func part2(file io.Reader) any {
+	loadPuzzle(file)
 	state = PuzzleState{startX, startY, startDir, 0}
 	lastFacing[Pos{startX, startY}] = startDir
 	// printPuzzleState()
@@ -280,23 +342,18 @@ func part2() {
 
 	password := 1000*row + 4*col + facing
 	fmt.Printf("Ended at row=%d, col=%d, facing=%d, password=%d\n", row, col, facing, password)
-	println(password)
+	return password
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
-	}
-
-	file, _ := os.Open(filename)
-	defer file.Close()
-	loadPuzzle(file)
-	method()
+func init() {
+	aoc.Register(aoc.Day{
+		Year:         2022,
+		Number:       22,
+		Title:        "Monkey Map",
+		Input:        "day22/input.txt",
+		Examples:     []string{"day22/intro.txt"},
+		Parts:        []aoc.Solver{part1, part2},
+		Capabilities: aoc.Visualisable,
+		Validate:     validatePuzzle,
+	})
 }
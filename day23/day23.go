@@ -1,10 +1,12 @@
-package main
+package day23
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
-	"os"
-	"strings"
+	"io"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
 var board [][]bool
@@ -48,7 +50,11 @@ var movementScanning = map[int][]int{
 
 var movementOrder = []int{North, South, West, East}
 
-func loadPuzzle(file *os.File) {
+func loadPuzzle(input io.Reader) {
+	// the input is scanned twice, so buffer it to allow seeking back to the start
+	data, _ := io.ReadAll(input)
+	file := bytes.NewReader(data)
+	movementOrder = []int{North, South, West, East}
 	width := -1
 	height := 0
 	scanner := bufio.NewScanner(file)
@@ -220,7 +226,8 @@ func moveElves() bool {
 	return moved
 }
 
-func part1() {
+func part1(file io.Reader) any {
+	loadPuzzle(file)
 	println("== Initial State ==")
 	printBoard()
 	roundsRemaining := 10
@@ -243,11 +250,12 @@ func part1() {
 			}
 		}
 	}
-	println(emptyCount)
+	return emptyCount
 
 }
 
-func part2() {
+func part2(file io.Reader) any {
+	loadPuzzle(file)
 	currentRound := 0
 
 	moved := true
@@ -256,23 +264,17 @@ func part2() {
 		moved = moveElves()
 	}
 
-	println(currentRound)
+	return currentRound
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
-	}
-
-	file, _ := os.Open(filename)
-	defer file.Close()
-	loadPuzzle(file)
-	method()
+func init() {
+	aoc.Register(aoc.Day{
+		Year:         2022,
+		Number:       23,
+		Title:        "Unstable Diffusion",
+		Input:        "day23/input.txt",
+		Examples:     []string{"day23/intro.txt"},
+		Parts:        []aoc.Solver{part1, part2},
+		Capabilities: aoc.Visualisable,
+	})
 }
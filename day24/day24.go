@@ -1,9 +1,11 @@
-package main
+package day24
 
 import (
 	"bufio"
-	"os"
+	"io"
 	"strings"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
 var maze [][]string
@@ -20,7 +22,7 @@ var dirs = []Pos{
 	{1, 0},
 }
 
-func loadPuzzle(file *os.File) {
+func loadPuzzle(file io.Reader) {
 	maze = make([][]string, 0)
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
@@ -102,33 +104,28 @@ func search(start Pos, exit Pos) int {
 
 }
 
-func part1() {
+func part1(file io.Reader) any {
+	loadPuzzle(file)
 	height := len(maze)
 	width := len(maze[0])
 	start := Pos{-1, 0}
 	exit := Pos{width - 1, height}
 
 	steps := search(start, exit)
-	println(steps)
+	return steps
 }
 
-func part2() {
+func part2(file io.Reader) any {
+	return nil
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
-	}
-
-	file, _ := os.Open(filename)
-	defer file.Close()
-	loadPuzzle(file)
-	method()
+func init() {
+	aoc.Register(aoc.Day{
+		Year:     2022,
+		Number:   24,
+		Title:    "Blizzard Basin",
+		Input:    "day24/input.txt",
+		Examples: []string{"day24/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+	})
 }
@@ -1,11 +1,13 @@
-package main
+package day25
 
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"math"
-	"os"
 	"strings"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
 var strValues = map[string]int{
@@ -26,7 +28,7 @@ var valueStrs = map[int]string{
 
 var fuelRequirements []string
 
-func loadPuzzle(file *os.File) {
+func loadPuzzle(file io.Reader) {
 	fuelRequirements = make([]string, 0)
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
@@ -98,7 +100,8 @@ func addSnafu(a, b string) string {
 	return snafu
 }
 
-func part1() {
+func part1(file io.Reader) any {
+	loadPuzzle(file)
 	// initially I added in decimal, but convering from dec to snafu is a pain
 	total := 0
 	for _, v := range fuelRequirements {
@@ -112,25 +115,20 @@ func part1() {
 		snafuTotal = addSnafu(snafuTotal, v)
 	}
 	fmt.Printf("Snafu total: %s\n", snafuTotal)
+	return snafuTotal
 }
 
-func part2() {
+func part2(file io.Reader) any {
+	return nil
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
-	}
-
-	file, _ := os.Open(filename)
-	defer file.Close()
-	loadPuzzle(file)
-	method()
+func init() {
+	aoc.Register(aoc.Day{
+		Year:     2022,
+		Number:   25,
+		Title:    "Full of Hot Air",
+		Input:    "day25/input.txt",
+		Examples: []string{"day25/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+	})
 }
@@ -0,0 +1,3 @@
+module citro.net/advent-2022-go/lib
+
+go 1.20
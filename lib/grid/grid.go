@@ -0,0 +1,129 @@
+// Package grid works with rectangular grids of cells, like the height maps, forests and boards
+// that so many days read from their input
+package grid
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"citro.net/advent-2022-go/lib/ints"
+)
+
+// Point is a cell's position, with X counting columns to the right and Y counting rows down
+type Point struct {
+	X, Y int
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("%d,%d", p.X, p.Y)
+}
+
+func (p Point) Add(q Point) Point {
+	return Point{p.X + q.X, p.Y + q.Y}
+}
+
+// Manhattan is the distance from p to q moving only along rows and columns
+func (p Point) Manhattan(q Point) int {
+	return ints.Abs(p.X-q.X) + ints.Abs(p.Y-q.Y)
+}
+
+// Orthogonal is the four steps to the cells sharing an edge, right, down, left and up
+var Orthogonal = []Point{{1, 0}, {0, 1}, {-1, 0}, {0, -1}}
+
+// Around is the eight steps to the cells sharing an edge or a corner, clockwise from the right
+var Around = []Point{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+
+// Grid is W columns by H rows of cells, stored a row at a time
+type Grid[T any] struct {
+	W, H  int
+	cells []T
+}
+
+func New[T any](w int, h int) *Grid[T] {
+	return &Grid[T]{W: w, H: h, cells: make([]T, w*h)}
+}
+
+// In is whether p is on the grid
+func (g *Grid[T]) In(p Point) bool {
+	return p.X >= 0 && p.X < g.W && p.Y >= 0 && p.Y < g.H
+}
+
+func (g *Grid[T]) At(p Point) T {
+	return g.cells[p.Y*g.W+p.X]
+}
+
+func (g *Grid[T]) Set(p Point, v T) {
+	g.cells[p.Y*g.W+p.X] = v
+}
+
+// Neighbours is the cells a step in each of steps away from p, leaving out any off the grid
+func (g *Grid[T]) Neighbours(p Point, steps []Point) []Point {
+	neighbours := make([]Point, 0, len(steps))
+	for _, step := range steps {
+		if q := p.Add(step); g.In(q) {
+			neighbours = append(neighbours, q)
+		}
+	}
+	return neighbours
+}
+
+// Find is the first cell, reading a row at a time, that match holds for
+func (g *Grid[T]) Find(match func(v T) bool) (Point, bool) {
+	for i, v := range g.cells {
+		if match(v) {
+			return Point{i % g.W, i / g.W}, true
+		}
+	}
+	return Point{}, false
+}
+
+// Render draws the grid a row at a time, with cell drawing each cell as a single character
+func (g *Grid[T]) Render(w io.Writer, cell func(p Point, v T) byte) error {
+	row := make([]byte, g.W+1)
+	row[g.W] = '\n'
+	for y := 0; y < g.H; y++ {
+		for x := 0; x < g.W; x++ {
+			row[x] = cell(Point{x, y}, g.cells[y*g.W+x])
+		}
+		if _, err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Parse reads a grid drawn a character per cell, one row per line, with cell turning each
+// character into a value.  every row has to be as wide as the first, and blank lines at the end
+// are ignored
+func Parse[T any](r io.Reader, cell func(p Point, c byte) (T, error)) (*Grid[T], error) {
+	g := &Grid[T]{}
+	blank := 0
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSuffix(sc.Text(), "\r")
+		if line == "" {
+			blank++
+			continue
+		}
+		if blank > 0 {
+			return nil, fmt.Errorf("line %d: blank line in the middle of the grid", g.H+blank)
+		}
+		if g.H == 0 {
+			g.W = len(line)
+		} else if len(line) != g.W {
+			return nil, fmt.Errorf("line %d: %d cells in a row, but the first row has %d", g.H+1, len(line), g.W)
+		}
+
+		for x := 0; x < len(line); x++ {
+			v, err := cell(Point{x, g.H}, line[x])
+			if err != nil {
+				return nil, fmt.Errorf("line %d, column %d: %w", g.H+1, x+1, err)
+			}
+			g.cells = append(g.cells, v)
+		}
+		g.H++
+	}
+	return g, sc.Err()
+}
@@ -0,0 +1,132 @@
+package grid
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func digit(p Point, c byte) (int, error) {
+	if c < '0' || c > '9' {
+		return 0, fmt.Errorf("%q isn't a digit", c)
+	}
+	return int(c - '0'), nil
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		err   string
+	}{
+		{"30373\n25512\n65332\n", "30373\n25512\n65332\n", ""},
+		{"12\r\n34\r\n\r\n", "12\n34\n", ""},
+		{"", "", ""},
+		{"123\n45\n", "", "line 2: 2 cells in a row, but the first row has 3"},
+		{"12\n\n34\n", "", "line 2: blank line in the middle of the grid"},
+		{"12\n3x\n", "", `line 2, column 2: 'x' isn't a digit`},
+	}
+
+	for _, test := range tests {
+		g, err := Parse(strings.NewReader(test.input), digit)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%q: got error %v, want %s", test.input, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.input, err)
+			continue
+		}
+
+		sb := strings.Builder{}
+		if err := g.Render(&sb, func(p Point, v int) byte { return byte('0' + v) }); err != nil {
+			t.Fatal(err)
+		}
+		if sb.String() != test.want {
+			t.Errorf("%q: rendered %q, want %q", test.input, sb.String(), test.want)
+		}
+	}
+}
+
+func TestGrid(t *testing.T) {
+	g, err := Parse(strings.NewReader("123\n456\n"), digit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.W != 3 || g.H != 2 {
+		t.Fatalf("got a %dx%d grid, want 3x2", g.W, g.H)
+	}
+	if v := g.At(Point{2, 1}); v != 6 {
+		t.Errorf("At(2,1) is %d, want 6", v)
+	}
+	g.Set(Point{0, 1}, 9)
+	if v := g.At(Point{0, 1}); v != 9 {
+		t.Errorf("At(0,1) is %d after setting it to 9", v)
+	}
+
+	for _, test := range []struct {
+		p    Point
+		want bool
+	}{{Point{0, 0}, true}, {Point{2, 1}, true}, {Point{3, 0}, false}, {Point{0, 2}, false}, {Point{-1, 0}, false}, {Point{0, -1}, false}} {
+		if g.In(test.p) != test.want {
+			t.Errorf("In(%s) is %t, want %t", test.p, g.In(test.p), test.want)
+		}
+	}
+
+	if p, ok := g.Find(func(v int) bool { return v == 5 }); !ok || p != (Point{1, 1}) {
+		t.Errorf("Find(== 5) is %s, %t, want 1,1", p, ok)
+	}
+	if _, ok := g.Find(func(v int) bool { return v > 9 }); ok {
+		t.Error("Find(> 9) found something")
+	}
+}
+
+func TestNeighbours(t *testing.T) {
+	g := New[bool](3, 3)
+	tests := []struct {
+		p     Point
+		steps []Point
+		want  string
+	}{
+		{Point{1, 1}, Orthogonal, "[0,1 1,0 1,2 2,1]"},
+		{Point{0, 0}, Orthogonal, "[0,1 1,0]"},
+		{Point{2, 2}, Orthogonal, "[1,2 2,1]"},
+		{Point{1, 1}, Around, "[0,0 0,1 0,2 1,0 1,2 2,0 2,1 2,2]"},
+		{Point{0, 2}, Around, "[0,1 1,1 1,2]"},
+	}
+
+	for _, test := range tests {
+		neighbours := []string{}
+		for _, q := range g.Neighbours(test.p, test.steps) {
+			neighbours = append(neighbours, q.String())
+		}
+		sort.Strings(neighbours)
+		if got := fmt.Sprint(neighbours); got != test.want {
+			t.Errorf("neighbours of %s: got %s, want %s", test.p, got, test.want)
+		}
+	}
+}
+
+func TestManhattan(t *testing.T) {
+	if d := (Point{1, -2}).Manhattan(Point{-3, 4}); d != 10 {
+		t.Errorf("got %d, want 10", d)
+	}
+}
+
+func TestRenderStopsOnError(t *testing.T) {
+	g := New[int](2, 3)
+	err := g.Render(failingWriter{}, func(p Point, v int) byte { return '.' })
+	if err == nil {
+		t.Error("expected the writer's error")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("full")
+}
@@ -0,0 +1,252 @@
+// Package intervals works with closed integer intervals, like the section assignments in 2022 day 4
+// or the row coverage in 2022 day 15
+package intervals
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Interval covers every integer from Start to End, including both ends
+type Interval struct {
+	Start int
+	End   int
+}
+
+func (a Interval) String() string {
+	if a.Start == a.End {
+		return fmt.Sprint(a.Start)
+	}
+	return fmt.Sprintf("%d-%d", a.Start, a.End)
+}
+
+func (a Interval) Len() int {
+	if a.End < a.Start {
+		return 0
+	}
+	return a.End - a.Start + 1
+}
+
+func (a Interval) Contains(b Interval) bool {
+	return a.Start <= b.Start && a.End >= b.End
+}
+
+func (a Interval) Overlaps(b Interval) bool {
+	return a.Start <= b.End && b.Start <= a.End
+}
+
+func (a Interval) ContainsPoint(p int) bool {
+	return a.Start <= p && p <= a.End
+}
+
+// Set is a union of intervals, kept as a sorted list of disjoint intervals.  intervals that
+// touch, like 1-3 and 4-5, are merged since there are no integers between them
+type Set struct {
+	intervals []Interval
+}
+
+// Intervals returns the disjoint intervals making up the set, in order
+func (s *Set) Intervals() []Interval {
+	return append([]Interval{}, s.intervals...)
+}
+
+// Insert adds a to the set, merging it with anything it overlaps or touches
+func (s *Set) Insert(a Interval) {
+	if a.Len() == 0 {
+		return
+	}
+
+	// the first interval that ends at or after the one just before a
+	i := sort.Search(len(s.intervals), func(i int) bool {
+		return s.intervals[i].End >= a.Start-1
+	})
+	j := i
+	for j < len(s.intervals) && s.intervals[j].Start <= a.End+1 {
+		if s.intervals[j].Start < a.Start {
+			a.Start = s.intervals[j].Start
+		}
+		if s.intervals[j].End > a.End {
+			a.End = s.intervals[j].End
+		}
+		j++
+	}
+
+	merged := append(append(append([]Interval{}, s.intervals[:i]...), a), s.intervals[j:]...)
+	s.intervals = merged
+}
+
+// Subtract removes a from the set, splitting any interval it falls in the middle of
+func (s *Set) Subtract(a Interval) {
+	if a.Len() == 0 {
+		return
+	}
+
+	remaining := make([]Interval, 0, len(s.intervals)+1)
+	for _, b := range s.intervals {
+		if !b.Overlaps(a) {
+			remaining = append(remaining, b)
+			continue
+		}
+		if b.Start < a.Start {
+			remaining = append(remaining, Interval{b.Start, a.Start - 1})
+		}
+		if b.End > a.End {
+			remaining = append(remaining, Interval{a.End + 1, b.End})
+		}
+	}
+	s.intervals = remaining
+}
+
+// Len is the number of integers covered by the set
+func (s *Set) Len() int {
+	n := 0
+	for _, a := range s.intervals {
+		n += a.Len()
+	}
+	return n
+}
+
+func (s *Set) Contains(p int) bool {
+	i := sort.Search(len(s.intervals), func(i int) bool {
+		return s.intervals[i].End >= p
+	})
+	return i < len(s.intervals) && s.intervals[i].Start <= p
+}
+
+// Gaps is the parts of within that the set doesn't cover
+func (s *Set) Gaps(within Interval) []Interval {
+	gaps := Set{}
+	gaps.Insert(within)
+	for _, a := range s.intervals {
+		gaps.Subtract(a)
+	}
+	return gaps.intervals
+}
+
+// Segment is a stretch of integers that the same number of intervals cover
+type Segment struct {
+	Interval
+	Depth int
+}
+
+// Coverage sweeps over intervals that may overlap, splitting the range they span into segments by
+// how many of the intervals cover each one.  stretches nothing covers come back with a depth of 0
+func Coverage(intervals []Interval) []Segment {
+	type event struct {
+		at    int
+		delta int
+	}
+	events := []event{}
+	for _, a := range intervals {
+		if a.Len() > 0 {
+			events = append(events, event{a.Start, 1}, event{a.End + 1, -1})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].at < events[j].at
+	})
+
+	segments := []Segment{}
+	depth := 0
+	for i := 0; i < len(events); {
+		at := events[i].at
+		for ; i < len(events) && events[i].at == at; i++ {
+			depth += events[i].delta
+		}
+		if i < len(events) {
+			segments = append(segments, Segment{Interval{at, events[i].at - 1}, depth})
+		}
+	}
+	return segments
+}
+
+// Index answers stabbing queries, which of a fixed list of intervals cover a point, in
+// O(log n + k) using a centered interval tree.  results are indexes into the original list
+type Index struct {
+	intervals []Interval
+	root      *indexNode
+}
+
+type indexNode struct {
+	center      int
+	byStart     []int
+	byEnd       []int
+	left, right *indexNode
+}
+
+func NewIndex(intervals []Interval) *Index {
+	ids := make([]int, 0, len(intervals))
+	for id, a := range intervals {
+		if a.Len() > 0 {
+			ids = append(ids, id)
+		}
+	}
+
+	idx := &Index{intervals: append([]Interval{}, intervals...)}
+	idx.root = idx.build(ids)
+	return idx
+}
+
+func (idx *Index) build(ids []int) *indexNode {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// center on the median endpoint so both sides shrink
+	points := make([]int, 0, len(ids)*2)
+	for _, id := range ids {
+		points = append(points, idx.intervals[id].Start, idx.intervals[id].End)
+	}
+	sort.Ints(points)
+	n := &indexNode{center: points[len(points)/2]}
+
+	var left, right []int
+	for _, id := range ids {
+		a := idx.intervals[id]
+		if a.End < n.center {
+			left = append(left, id)
+		} else if a.Start > n.center {
+			right = append(right, id)
+		} else {
+			n.byStart = append(n.byStart, id)
+		}
+	}
+	n.byEnd = append([]int{}, n.byStart...)
+	sort.Slice(n.byStart, func(i, j int) bool {
+		return idx.intervals[n.byStart[i]].Start < idx.intervals[n.byStart[j]].Start
+	})
+	sort.Slice(n.byEnd, func(i, j int) bool {
+		return idx.intervals[n.byEnd[i]].End > idx.intervals[n.byEnd[j]].End
+	})
+
+	n.left = idx.build(left)
+	n.right = idx.build(right)
+	return n
+}
+
+// Stab returns the indexes of every interval covering p, in ascending order
+func (idx *Index) Stab(p int) []int {
+	found := []int{}
+	for n := idx.root; n != nil; {
+		if p < n.center {
+			// everything here reaches the center, so only the start can rule it out
+			for _, id := range n.byStart {
+				if idx.intervals[id].Start > p {
+					break
+				}
+				found = append(found, id)
+			}
+			n = n.left
+		} else {
+			for _, id := range n.byEnd {
+				if idx.intervals[id].End < p {
+					break
+				}
+				found = append(found, id)
+			}
+			n = n.right
+		}
+	}
+	sort.Ints(found)
+	return found
+}
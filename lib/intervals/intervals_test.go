@@ -0,0 +1,149 @@
+package intervals
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	type op struct {
+		subtract bool
+		a        Interval
+	}
+	tests := []struct {
+		name string
+		ops  []op
+		want string
+		len  int
+	}{
+		{"empty", nil, "[]", 0},
+		{"one", []op{{false, Interval{1, 3}}}, "[1-3]", 3},
+		{"point", []op{{false, Interval{4, 4}}}, "[4]", 1},
+		{"backwards is empty", []op{{false, Interval{3, 1}}}, "[]", 0},
+		{"disjoint", []op{{false, Interval{5, 6}}, {false, Interval{1, 2}}}, "[1-2 5-6]", 4},
+		{"touching merge", []op{{false, Interval{1, 3}}, {false, Interval{4, 5}}}, "[1-5]", 5},
+		{"overlapping merge", []op{{false, Interval{1, 4}}, {false, Interval{3, 8}}}, "[1-8]", 8},
+		{"bridges several", []op{{false, Interval{1, 2}}, {false, Interval{5, 6}}, {false, Interval{9, 10}}, {false, Interval{2, 9}}}, "[1-10]", 10},
+		{"inside", []op{{false, Interval{1, 10}}, {false, Interval{3, 4}}}, "[1-10]", 10},
+		{"negative", []op{{false, Interval{-5, -3}}, {false, Interval{-1, 1}}}, "[-5--3 -1-1]", 6},
+		{"subtract middle", []op{{false, Interval{1, 10}}, {true, Interval{4, 6}}}, "[1-3 7-10]", 7},
+		{"subtract end", []op{{false, Interval{1, 10}}, {true, Interval{8, 12}}}, "[1-7]", 7},
+		{"subtract all", []op{{false, Interval{1, 2}}, {false, Interval{4, 5}}, {true, Interval{0, 6}}}, "[]", 0},
+		{"subtract nothing", []op{{false, Interval{1, 2}}, {true, Interval{3, 4}}}, "[1-2]", 2},
+	}
+
+	for _, test := range tests {
+		s := Set{}
+		for _, op := range test.ops {
+			if op.subtract {
+				s.Subtract(op.a)
+			} else {
+				s.Insert(op.a)
+			}
+		}
+		if got := fmt.Sprint(s.Intervals()); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, got, test.want)
+		}
+		if s.Len() != test.len {
+			t.Errorf("%s: got length %d, want %d", test.name, s.Len(), test.len)
+		}
+	}
+}
+
+func TestSetMatchesPoints(t *testing.T) {
+	rng := rand.New(rand.NewSource(35))
+	for round := 0; round < 50; round++ {
+		s := Set{}
+		covered := map[int]bool{}
+		for i := 0; i < 8; i++ {
+			start := rng.Intn(40) - 20
+			a := Interval{start, start + rng.Intn(8)}
+			subtract := rng.Intn(3) == 0
+			if subtract {
+				s.Subtract(a)
+			} else {
+				s.Insert(a)
+			}
+			for p := a.Start; p <= a.End; p++ {
+				covered[p] = !subtract
+			}
+		}
+
+		n := 0
+		for p := -30; p <= 30; p++ {
+			if covered[p] {
+				n++
+			}
+			if s.Contains(p) != covered[p] {
+				t.Fatalf("%v: Contains(%d) is %t, want %t", s.Intervals(), p, s.Contains(p), covered[p])
+			}
+		}
+		if s.Len() != n {
+			t.Fatalf("%v: got length %d, want %d", s.Intervals(), s.Len(), n)
+		}
+
+		gaps := 0
+		for _, g := range s.Gaps(Interval{-30, 30}) {
+			for p := g.Start; p <= g.End; p++ {
+				if covered[p] {
+					t.Fatalf("%v: gap %v covers %d", s.Intervals(), g, p)
+				}
+				gaps++
+			}
+		}
+		if gaps != 61-n {
+			t.Fatalf("%v: gaps cover %d points, want %d", s.Intervals(), gaps, 61-n)
+		}
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	tests := []struct {
+		intervals []Interval
+		want      string
+	}{
+		{nil, "[]"},
+		{[]Interval{{1, 3}}, "[1-3x1]"},
+		{[]Interval{{1, 3}, {5, 6}}, "[1-3x1 4x0 5-6x1]"},
+		{[]Interval{{1, 5}, {3, 7}}, "[1-2x1 3-5x2 6-7x1]"},
+		{[]Interval{{1, 3}, {4, 6}}, "[1-3x1 4-6x1]"},
+		{[]Interval{{2, 4}, {2, 4}, {3, 3}}, "[2x2 3x3 4x2]"},
+		{[]Interval{{3, 1}, {2, 2}}, "[2x1]"},
+	}
+
+	for _, test := range tests {
+		segments := []string{}
+		for _, s := range Coverage(test.intervals) {
+			segments = append(segments, fmt.Sprintf("%sx%d", s.Interval, s.Depth))
+		}
+		if got := fmt.Sprint(segments); got != test.want {
+			t.Errorf("%v: got %s, want %s", test.intervals, got, test.want)
+		}
+	}
+}
+
+func TestIndexMatchesScan(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	for round := 0; round < 50; round++ {
+		intervals := make([]Interval, rng.Intn(30))
+		for i := range intervals {
+			start := rng.Intn(60) - 30
+			// a few come out backwards, which cover nothing
+			intervals[i] = Interval{start, start + rng.Intn(15) - 2}
+		}
+		idx := NewIndex(intervals)
+
+		for p := -35; p <= 45; p++ {
+			want := []int{}
+			for id, a := range intervals {
+				if a.ContainsPoint(p) {
+					want = append(want, id)
+				}
+			}
+			if got := idx.Stab(p); fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Fatalf("%v: Stab(%d) is %v, want %v", intervals, p, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Package ints has the small integer helpers that nearly every day ends up writing for itself
+package ints
+
+// Integer is any of go's built in integer types
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+func Abs[T Integer](x T) T {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func Min[T Integer](x T, rest ...T) T {
+	for _, y := range rest {
+		if y < x {
+			x = y
+		}
+	}
+	return x
+}
+
+func Max[T Integer](x T, rest ...T) T {
+	for _, y := range rest {
+		if y > x {
+			x = y
+		}
+	}
+	return x
+}
+
+// GCD is the greatest common divisor of a and b, which is never negative
+func GCD[T Integer](a, b T) T {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return Abs(a)
+}
+
+// LCM is the least common multiple of every value, or 0 if there are none
+func LCM[T Integer](values ...T) T {
+	if len(values) == 0 {
+		return 0
+	}
+
+	l := Abs(values[0])
+	for _, v := range values[1:] {
+		if l == 0 || v == 0 {
+			return 0
+		}
+		l = l / GCD(l, v) * Abs(v)
+	}
+	return l
+}
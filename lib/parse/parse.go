@@ -0,0 +1,80 @@
+// Package parse has the input reading that most days start with, lines, blocks of lines split up
+// by blank ones, and the numbers buried in a line of prose
+package parse
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Lines reads every line of r, without its line ending, whether that's \n or \r\n
+func Lines(r io.Reader) ([]string, error) {
+	lines := []string{}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		lines = append(lines, strings.TrimSuffix(sc.Text(), "\r"))
+	}
+	return lines, sc.Err()
+}
+
+// Blocks reads r as groups of lines with blank lines between them, like the elves' inventories
+// or the monkeys' notes.  any number of blank lines separate two blocks, and none come out empty
+func Blocks(r io.Reader) ([][]string, error) {
+	lines, err := Lines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := [][]string{}
+	block := []string{}
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			if len(block) > 0 {
+				blocks = append(blocks, block)
+				block = []string{}
+			}
+			continue
+		}
+		block = append(block, line)
+	}
+	if len(block) > 0 {
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// Ints finds every integer in s, ignoring whatever is around them, so "Sensor at x=2, y=-18"
+// gives 2 and -18.  a minus sign only counts when it's right before the digits and doesn't follow
+// another digit or letter, so ranges like 2-4 give 2 and 4
+func Ints(s string) []int {
+	values := []int{}
+	for i := 0; i < len(s); {
+		if !isDigit(s[i]) {
+			i++
+			continue
+		}
+
+		start := i
+		if start > 0 && s[start-1] == '-' && (start == 1 || !isWordByte(s[start-2])) {
+			start--
+		}
+		for i < len(s) && isDigit(s[i]) {
+			i++
+		}
+		// a run of digits too long for an int is left out rather than wrapped
+		if v, err := strconv.Atoi(s[start:i]); err == nil {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordByte(c byte) bool {
+	return isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,77 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "[]"},
+		{"a", "[a]"},
+		{"a\nb\n", "[a b]"},
+		{"a\r\nb\r\n", "[a b]"},
+		{"a\n\nb", "[a  b]"},
+	}
+
+	for _, test := range tests {
+		lines, err := Lines(strings.NewReader(test.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fmt.Sprint(lines); got != test.want {
+			t.Errorf("%q: got %s, want %s", test.input, got, test.want)
+		}
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "[]"},
+		{"1000\n2000\n\n4000\n", "[[1000 2000] [4000]]"},
+		{"\n\n1\n\n\n\n2\n3\n\n", "[[1] [2 3]]"},
+		{"1\r\n\r\n2\r\n", "[[1] [2]]"},
+		{"a\n  \nb", "[[a] [b]]"},
+	}
+
+	for _, test := range tests {
+		blocks, err := Blocks(strings.NewReader(test.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fmt.Sprint(blocks); got != test.want {
+			t.Errorf("%q: got %s, want %s", test.input, got, test.want)
+		}
+	}
+}
+
+func TestInts(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"", "[]"},
+		{"no numbers", "[]"},
+		{"42", "[42]"},
+		{"-42", "[-42]"},
+		{"Sensor at x=2, y=-18: closest beacon is at x=-2, y=15", "[2 -18 -2 15]"},
+		{"2-4,6-8", "[2 4 6 8]"},
+		{"move 10 from 2 to 11", "[10 2 11]"},
+		{"x-1 and 5 - 3 and --7", "[1 5 3 -7]"},
+		{"Blueprint 1: Each ore robot costs 4 ore.", "[1 4]"},
+		{"99999999999999999999 7", "[7]"},
+	}
+
+	for _, test := range tests {
+		if got := fmt.Sprint(Ints(test.s)); got != test.want {
+			t.Errorf("%q: got %s, want %s", test.s, got, test.want)
+		}
+	}
+}
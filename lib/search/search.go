@@ -0,0 +1,137 @@
+// Package search finds paths through graphs that are given as a func listing a node's neighbours,
+// so a day never has to build its graph up front
+package search
+
+import "container/heap"
+
+// BFS finds a path with the fewest steps from any of starts to a node that goal holds for.  the
+// path runs from the start it left from to the goal, including both, and ok is false if no goal
+// can be reached
+func BFS[N comparable](starts []N, neighbours func(n N) []N, goal func(n N) bool) (path []N, ok bool) {
+	cameFrom := map[N]N{}
+	seen := map[N]bool{}
+	queue := []N{}
+	for _, s := range starts {
+		if !seen[s] {
+			seen[s] = true
+			queue = append(queue, s)
+		}
+	}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if goal(n) {
+			return reconstruct(cameFrom, n), true
+		}
+		for _, m := range neighbours(n) {
+			if !seen[m] {
+				seen[m] = true
+				cameFrom[m] = n
+				queue = append(queue, m)
+			}
+		}
+	}
+	return nil, false
+}
+
+// Distances is how many steps each node that can be reached from starts is from the nearest of them
+func Distances[N comparable](starts []N, neighbours func(n N) []N) map[N]int {
+	distances := map[N]int{}
+	queue := []N{}
+	for _, s := range starts {
+		if _, ok := distances[s]; !ok {
+			distances[s] = 0
+			queue = append(queue, s)
+		}
+	}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, m := range neighbours(n) {
+			if _, ok := distances[m]; !ok {
+				distances[m] = distances[n] + 1
+				queue = append(queue, m)
+			}
+		}
+	}
+	return distances
+}
+
+// Step is an edge to a neighbour, and what it costs to take it.  costs can't be negative
+type Step[N comparable] struct {
+	To   N
+	Cost int
+}
+
+// Dijkstra finds the cheapest path from any of starts to a node that goal holds for, returning it
+// as BFS does along with what it costs
+func Dijkstra[N comparable](starts []N, neighbours func(n N) []Step[N], goal func(n N) bool) (path []N, cost int, ok bool) {
+	cameFrom := map[N]N{}
+	costs := map[N]int{}
+	done := map[N]bool{}
+	q := &queue[N]{}
+	for _, s := range starts {
+		costs[s] = 0
+		heap.Push(q, queued[N]{s, 0})
+	}
+
+	for q.Len() > 0 {
+		e := heap.Pop(q).(queued[N])
+		// a node is queued again whenever a cheaper way to it turns up, so skip the stale entries
+		if done[e.node] {
+			continue
+		}
+		done[e.node] = true
+		if goal(e.node) {
+			return reconstruct(cameFrom, e.node), e.cost, true
+		}
+
+		for _, step := range neighbours(e.node) {
+			c := e.cost + step.Cost
+			if known, ok := costs[step.To]; !ok || c < known {
+				costs[step.To] = c
+				cameFrom[step.To] = e.node
+				heap.Push(q, queued[N]{step.To, c})
+			}
+		}
+	}
+	return nil, 0, false
+}
+
+// reconstruct follows cameFrom back from n to the start, which has no entry in it
+func reconstruct[N comparable](cameFrom map[N]N, n N) []N {
+	path := []N{n}
+	for {
+		prev, ok := cameFrom[n]
+		if !ok {
+			break
+		}
+		path = append(path, prev)
+		n = prev
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+type queued[N comparable] struct {
+	node N
+	cost int
+}
+
+// queue is a min heap of nodes by cost, for container/heap
+type queue[N comparable] []queued[N]
+
+func (q queue[N]) Len() int           { return len(q) }
+func (q queue[N]) Less(i, j int) bool { return q[i].cost < q[j].cost }
+func (q queue[N]) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *queue[N]) Push(x any)        { *q = append(*q, x.(queued[N])) }
+func (q *queue[N]) Pop() any {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,137 @@
+package search
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// line is a graph of the integers, where each one leads to the next and the one after that
+func line(n int) []int {
+	return []int{n + 1, n + 2}
+}
+
+func TestBFS(t *testing.T) {
+	tests := []struct {
+		starts []int
+		goal   int
+		want   string
+		ok     bool
+	}{
+		{[]int{0}, 0, "[0]", true},
+		{[]int{0}, 1, "[0 1]", true},
+		{[]int{0}, 6, "[0 2 4 6]", true},
+		{[]int{0}, 7, "[0 1 3 5 7]", true},
+		{[]int{0, 5}, 7, "[5 7]", true},
+		{[]int{10}, 3, "[]", false},
+		{nil, 3, "[]", false},
+	}
+
+	for _, test := range tests {
+		neighbours := func(n int) []int {
+			// stop the graph somewhere so an unreachable goal ends the search
+			if n > 20 {
+				return nil
+			}
+			return line(n)
+		}
+		path, ok := BFS(test.starts, neighbours, func(n int) bool { return n == test.goal })
+		if ok != test.ok || fmt.Sprint(path) != test.want {
+			t.Errorf("%v to %d: got %v, %t, want %s, %t", test.starts, test.goal, path, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestDistances(t *testing.T) {
+	neighbours := func(n int) []int {
+		if n >= 6 {
+			return nil
+		}
+		return line(n)
+	}
+	got := Distances([]int{0, 3}, neighbours)
+	want := map[int]int{0: 0, 1: 1, 2: 1, 3: 0, 4: 1, 5: 1, 6: 2, 7: 2}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// grid is a w by h graph of cells numbered a row at a time, where stepping into a cell costs its weight
+type grid struct {
+	w, h    int
+	weights []int
+}
+
+func (g grid) steps(n int) []Step[int] {
+	steps := []Step[int]{}
+	x, y := n%g.w, n/g.w
+	for _, d := range [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+		nx, ny := x+d[0], y+d[1]
+		if nx >= 0 && nx < g.w && ny >= 0 && ny < g.h {
+			m := ny*g.w + nx
+			steps = append(steps, Step[int]{m, g.weights[m]})
+		}
+	}
+	return steps
+}
+
+func TestDijkstra(t *testing.T) {
+	// the cheap way round is longer than the expensive way through the middle
+	g := grid{3, 3, []int{
+		1, 1, 1,
+		9, 9, 1,
+		1, 1, 1,
+	}}
+	path, cost, ok := Dijkstra([]int{0}, g.steps, func(n int) bool { return n == 6 })
+	if !ok || cost != 6 || fmt.Sprint(path) != "[0 1 2 5 8 7 6]" {
+		t.Errorf("got %v costing %d, %t, want [0 1 2 5 8 7 6] costing 6", path, cost, ok)
+	}
+
+	if _, _, ok := Dijkstra([]int{0}, g.steps, func(n int) bool { return n == 9 }); ok {
+		t.Error("found a path to a cell that isn't there")
+	}
+}
+
+func TestDijkstraMatchesBFSWithUnitCosts(t *testing.T) {
+	rng := rand.New(rand.NewSource(30))
+	for round := 0; round < 50; round++ {
+		g := grid{1 + rng.Intn(8), 1 + rng.Intn(8), nil}
+		g.weights = make([]int, g.w*g.h)
+		for i := range g.weights {
+			g.weights[i] = 1
+		}
+		// walls are cells with no way in
+		walls := map[int]bool{}
+		for i := rng.Intn(g.w * g.h / 2); i > 0; i-- {
+			walls[rng.Intn(g.w*g.h)] = true
+		}
+		steps := func(n int) []Step[int] {
+			open := []Step[int]{}
+			for _, s := range g.steps(n) {
+				if !walls[s.To] {
+					open = append(open, s)
+				}
+			}
+			return open
+		}
+		neighbours := func(n int) []int {
+			ns := []int{}
+			for _, s := range steps(n) {
+				ns = append(ns, s.To)
+			}
+			return ns
+		}
+
+		goal := g.w*g.h - 1
+		isGoal := func(n int) bool { return n == goal }
+		bfsPath, bfsOK := BFS([]int{0}, neighbours, isGoal)
+		_, cost, ok := Dijkstra([]int{0}, steps, isGoal)
+		distance, reached := Distances([]int{0}, neighbours)[goal]
+		if ok != bfsOK || ok != reached {
+			t.Fatalf("round %d: Dijkstra found a path %t, BFS %t, Distances %t", round, ok, bfsOK, reached)
+		}
+		if ok && (cost != len(bfsPath)-1 || cost != distance) {
+			t.Fatalf("round %d: Dijkstra costs %d, BFS takes %d steps, Distances says %d", round, cost, len(bfsPath)-1, distance)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"citro.net/advent-2022-go/aoc"
+)
+
+// answerEntry holds the known answers for one input, keyed by its fingerprint in the store.
+// the day is recorded as well, so an input handed to the wrong day can be caught
+type answerEntry struct {
+	Day     int               `json:"day"`
+	Example bool              `json:"example"`
+	Answers map[string]string `json:"answers"`
+}
+
+type answerStore struct {
+	path    string
+	entries map[string]*answerEntry
+}
+
+// loadAnswers reads the store at path.  a missing file is just an empty store
+func loadAnswers(path string) (*answerStore, error) {
+	s := &answerStore{path: path, entries: map[string]*answerEntry{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// answerStores keeps a separate store in each year's directory, next to that year's inputs,
+// and loads each one the first time a day from that year needs it
+type answerStores struct {
+	root  string
+	name  string
+	years map[int]*answerStore
+}
+
+func newAnswerStores(root string, name string) *answerStores {
+	return &answerStores{root: root, name: name, years: map[int]*answerStore{}}
+}
+
+func (s *answerStores) forYear(year int) (*answerStore, error) {
+	if store, ok := s.years[year]; ok {
+		return store, nil
+	}
+
+	store, err := loadAnswers(filepath.Join(s.root, aoc.YearDir(year), s.name))
+	if err != nil {
+		return nil, err
+	}
+	s.years[year] = store
+	return store, nil
+}
+
+func (s *answerStore) save() error {
+	data, err := json.MarshalIndent(s.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, append(data, '\n'), 0644)
+}
+
+func (s *answerStore) lookup(fingerprint string) (*answerEntry, bool) {
+	e, ok := s.entries[fingerprint]
+	return e, ok
+}
+
+func (s *answerStore) expected(fingerprint string, part int) (string, bool) {
+	e, ok := s.entries[fingerprint]
+	if !ok {
+		return "", false
+	}
+	answer, ok := e.Answers[strconv.Itoa(part)]
+	return answer, ok
+}
+
+func (s *answerStore) record(fingerprint string, day int, example bool, part int, answer string) {
+	e, ok := s.entries[fingerprint]
+	if !ok {
+		e = &answerEntry{Answers: map[string]string{}}
+		s.entries[fingerprint] = e
+	}
+	e.Day = day
+	e.Example = example
+	e.Answers[strconv.Itoa(part)] = answer
+}
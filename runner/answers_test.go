@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"citro.net/advent-2022-go/aoc"
+)
+
+// TestAnswers solves every registered day's input and examples and compares each part with the
+// answer recorded for it.  parts with no recorded answer are skipped, so a new day is picked up
+// as soon as its answers are recorded.  with -short only the examples are solved
+func TestAnswers(t *testing.T) {
+	// the tests run in the runner's directory, so the years' directories are one level up
+	const root = ".."
+	stores := newAnswerStores(root, "answers.json")
+
+	// some days print traces as they go, which would swamp the test output
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	for _, d := range aoc.Days() {
+		store, err := stores.forYear(d.Year)
+		if err != nil {
+			t.Fatalf("%s: %v", d, err)
+		}
+
+		paths := d.Examples
+		if d.Input != "" && !testing.Short() {
+			paths = append([]string{d.Input}, paths...)
+		}
+		for _, path := range paths {
+			path = d.Path(root, path)
+			fingerprint, err := aoc.FingerprintFile(path)
+			if err != nil {
+				t.Errorf("%s: %v", d, err)
+				continue
+			}
+
+			for part := 1; part <= len(d.Parts); part++ {
+				expected, ok := store.expected(fingerprint, part)
+				if !ok {
+					continue
+				}
+				t.Run(fmt.Sprintf("%d/%02d/%d/%s", d.Year, d.Number, part, fingerprint[:8]), func(t *testing.T) {
+					f, err := os.Open(path)
+					if err != nil {
+						t.Fatal(err)
+					}
+					defer f.Close()
+
+					stdout := os.Stdout
+					os.Stdout = devNull
+					r, done, err := solve(d, part, f, nil, 0)
+					<-done
+					os.Stdout = stdout
+					if err != nil {
+						t.Fatal(err)
+					}
+					if r.Answer != expected {
+						t.Errorf("%s part %d on %s: got %q, want %q", d, part, path, r.Answer, expected)
+					}
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+// every 2022 day registers itself with aoc at init time, so importing the package is all it takes
+import (
+	_ "citro.net/advent-2022-go/day01"
+	_ "citro.net/advent-2022-go/day02"
+	_ "citro.net/advent-2022-go/day03"
+	_ "citro.net/advent-2022-go/day04"
+	_ "citro.net/advent-2022-go/day05"
+	_ "citro.net/advent-2022-go/day06"
+	_ "citro.net/advent-2022-go/day07"
+	_ "citro.net/advent-2022-go/day08"
+	_ "citro.net/advent-2022-go/day09"
+	_ "citro.net/advent-2022-go/day10"
+	_ "citro.net/advent-2022-go/day11"
+	_ "citro.net/advent-2022-go/day12"
+	_ "citro.net/advent-2022-go/day13"
+	_ "citro.net/advent-2022-go/day14"
+	_ "citro.net/advent-2022-go/day15"
+	_ "citro.net/advent-2022-go/day16"
+	_ "citro.net/advent-2022-go/day17"
+	_ "citro.net/advent-2022-go/day18"
+	_ "citro.net/advent-2022-go/day19"
+	_ "citro.net/advent-2022-go/day20"
+	_ "citro.net/advent-2022-go/day21"
+	_ "citro.net/advent-2022-go/day22"
+	_ "citro.net/advent-2022-go/day23"
+	_ "citro.net/advent-2022-go/day24"
+	_ "citro.net/advent-2022-go/day25"
+)
@@ -0,0 +1,3 @@
+module citro.net/advent-2022-go/runner
+
+go 1.20
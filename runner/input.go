@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"citro.net/advent-2022-go/aoc"
+)
+
+const (
+	inputExample = "example"
+	inputReal    = "real"
+	inputUnknown = "unknown"
+)
+
+// opener hands out a fresh copy of the input each time, since fingerprinting,
+// validation and every part all read it from the start
+type opener func() (io.ReadCloser, error)
+
+type inputInfo struct {
+	Fingerprint string   `json:"fingerprint"`
+	Kind        string   `json:"kind"`
+	Warnings    []string `json:"warnings,omitempty"`
+}
+
+func (i inputInfo) print() {
+	fmt.Printf("Input %s (%s)\n", i.Fingerprint, i.Kind)
+	for _, w := range i.Warnings {
+		fmt.Printf("warning: %s\n", w)
+	}
+}
+
+type knownInput struct {
+	day  *aoc.Day
+	kind string
+}
+
+// knownInputs fingerprints the inputs and examples that ship with every day of every year,
+// so an input can be recognised even before any answers have been recorded for it
+func knownInputs(root string) map[string]knownInput {
+	known := map[string]knownInput{}
+	add := func(d *aoc.Day, path string, kind string) {
+		fingerprint, err := aoc.FingerprintFile(d.Path(root, path))
+		if err == nil {
+			known[fingerprint] = knownInput{d, kind}
+		}
+	}
+
+	for _, d := range aoc.Days() {
+		add(d, d.Input, inputReal)
+		for _, path := range d.Examples {
+			add(d, path, inputExample)
+		}
+	}
+	return known
+}
+
+// inspectInput fingerprints the input, works out whose it is, and validates it for the parts
+// about to be solved
+func inspectInput(d *aoc.Day, root string, open opener, store *answerStore, parts []int) (inputInfo, error) {
+	r, err := open()
+	if err != nil {
+		return inputInfo{}, err
+	}
+	fingerprint, err := aoc.Fingerprint(r)
+	r.Close()
+	if err != nil {
+		return inputInfo{}, err
+	}
+
+	info := inputInfo{Fingerprint: fingerprint, Kind: inputUnknown}
+	if e, ok := store.lookup(fingerprint); ok {
+		info.Kind = inputReal
+		if e.Example {
+			info.Kind = inputExample
+		}
+		if e.Day != d.Number {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("answers for this input were recorded against day %d", e.Day))
+		}
+	} else if k, ok := knownInputs(root)[fingerprint]; ok {
+		info.Kind = k.kind
+		if k.day != d {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("this is the %s input for %s", k.kind, k.day))
+		}
+	}
+
+	// the examples are the puzzle's own, so there's nothing to warn about when a part can't
+	// handle one of them
+	if d.Validate != nil && info.Kind != inputExample {
+		warnings, err := validate(d, open, parts)
+		if err != nil {
+			return inputInfo{}, err
+		}
+		info.Warnings = append(info.Warnings, warnings...)
+	}
+
+	return info, nil
+}
+
+// validate runs the day's checks for each part.  parts that fail the same way share a warning,
+// so a check every part relies on isn't repeated for each of them
+func validate(d *aoc.Day, open opener, parts []int) ([]string, error) {
+	messages := []string{}
+	failed := map[string][]string{}
+	for _, part := range parts {
+		r, err := open()
+		if err != nil {
+			return nil, err
+		}
+		err = d.Validate(r, part)
+		r.Close()
+		if err == nil {
+			continue
+		}
+
+		if _, ok := failed[err.Error()]; !ok {
+			messages = append(messages, err.Error())
+		}
+		failed[err.Error()] = append(failed[err.Error()], strconv.Itoa(part))
+	}
+
+	warnings := []string{}
+	for _, m := range messages {
+		who := d.String()
+		if len(failed[m]) < len(d.Parts) {
+			who += " part " + strings.Join(failed[m], " and ")
+		}
+		warnings = append(warnings, fmt.Sprintf("%s may not handle this input: %s", who, m))
+	}
+	return warnings, nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"citro.net/advent-2022-go/aoc"
+)
+
+func TestValidate(t *testing.T) {
+	none := func(io.Reader) any { return nil }
+	d := &aoc.Day{
+		Year:   2022,
+		Number: 99,
+		Parts:  []aoc.Solver{none, none, none},
+		Validate: func(input io.Reader, part int) error {
+			data, _ := io.ReadAll(input)
+			switch {
+			case string(data) == "bad for all":
+				return errors.New("no good")
+			case string(data) == "bad for 2" && part == 2:
+				return errors.New("too small to fold")
+			case string(data) == "bad for 1 and 3" && part != 2:
+				return errors.New("odd")
+			}
+			return nil
+		},
+	}
+
+	tests := []struct {
+		input string
+		parts []int
+		want  []string
+	}{
+		{"fine", []int{1, 2, 3}, nil},
+		{"bad for 2", []int{1, 3}, nil},
+		{"bad for 2", []int{1, 2, 3}, []string{"2022 day 99 part 2 may not handle this input: too small to fold"}},
+		{"bad for 2", []int{2}, []string{"2022 day 99 part 2 may not handle this input: too small to fold"}},
+		{"bad for 1 and 3", []int{1, 2, 3}, []string{"2022 day 99 part 1 and 3 may not handle this input: odd"}},
+		{"bad for all", []int{1, 2, 3}, []string{"2022 day 99 may not handle this input: no good"}},
+		{"bad for all", []int{3}, []string{"2022 day 99 part 3 may not handle this input: no good"}},
+	}
+
+	for _, test := range tests {
+		open := func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(test.input)), nil
+		}
+		warnings, err := validate(d, open, test.parts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(warnings, "\n") != strings.Join(test.want, "\n") {
+			t.Errorf("%q with parts %v: got %q, want %q", test.input, test.parts, warnings, test.want)
+		}
+	}
+}
@@ -0,0 +1,427 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"citro.net/advent-2022-go/aoc"
+)
+
+type result struct {
+	Year     int         `json:"year"`
+	Day      int         `json:"day"`
+	Part     int         `json:"part,omitempty"`
+	Mode     string      `json:"mode,omitempty"`
+	Answer   string      `json:"answer"`
+	Elapsed  string      `json:"elapsed"`
+	Memory   memoryStats `json:"memory"`
+	Expected string      `json:"expected,omitempty"`
+	Correct  *bool       `json:"correct,omitempty"`
+}
+
+// options are the settings shared by every solve in a single run of the runner
+type options struct {
+	root      string
+	params    map[string]string
+	maxMemory uint64
+	answers   *answerStores
+	record    bool
+}
+
+// paramFlags collects repeated -p name=value overrides
+type paramFlags map[string]string
+
+func (p paramFlags) String() string {
+	return fmt.Sprint(map[string]string(p))
+}
+
+func (p paramFlags) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("expected name=value, got %q", s)
+	}
+	p[name] = value
+	return nil
+}
+
+// solve runs a single part with its heap watched.  done is closed once the solver has really
+// stopped, which can be after solve returns if it was aborted for going over maxMemory
+func solve(d *aoc.Day, part int, input io.Reader, params map[string]string, maxMemory uint64) (result, <-chan struct{}, error) {
+	if err := d.SetParams(params); err != nil {
+		return result{}, closedChan(), err
+	}
+
+	r, done, err := watched(d, maxMemory, func() (any, error) {
+		return d.Solve(part, input)
+	})
+	if err != nil {
+		return result{}, done, fmt.Errorf("%s part %d: %w", d, part, err)
+	}
+	r.Part = part
+	return r, done, nil
+}
+
+// watched runs fn with its heap watched and times it, see watchSolve
+func watched(d *aoc.Day, maxMemory uint64, fn func() (any, error)) (result, <-chan struct{}, error) {
+	var answer any
+	start := time.Now()
+	stats, done, err := watchSolve(maxMemory, func() error {
+		var err error
+		answer, err = fn()
+		return err
+	})
+	if err != nil {
+		return result{}, done, err
+	}
+
+	r := result{Year: d.Year, Day: d.Number, Elapsed: time.Since(start).String(), Memory: stats}
+	if answer != nil {
+		r.Answer = fmt.Sprint(answer)
+	}
+	return r, done, nil
+}
+
+func closedChan() <-chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}
+
+// check compares the answer with the one recorded for this input, if there is one
+func (r *result) check(store *answerStore, fingerprint string) {
+	expected, ok := store.expected(fingerprint, r.Part)
+	if !ok {
+		return
+	}
+
+	correct := r.Answer == expected
+	r.Expected = expected
+	r.Correct = &correct
+}
+
+func (r result) print() {
+	answer := r.Answer
+	if answer == "" {
+		answer = "(no answer)"
+	}
+	if strings.Contains(answer, "\n") {
+		answer = "\n" + strings.TrimSuffix(answer, "\n")
+	}
+
+	verdict := ""
+	if r.Correct != nil && *r.Correct {
+		verdict = "  [correct]"
+	} else if r.Correct != nil {
+		verdict = fmt.Sprintf("  [WRONG, expected %s]", r.Expected)
+	}
+
+	name := fmt.Sprintf("part %d", r.Part)
+	if r.Mode != "" {
+		name = r.Mode
+	}
+	fmt.Printf("%d day %d %s (%s, peak heap %s, %s allocated, %d GCs): %s%s\n",
+		r.Year, r.Day, name, r.Elapsed, formatBytes(r.Memory.PeakHeap), formatBytes(r.Memory.TotalAlloc), r.Memory.NumGC, answer, verdict)
+}
+
+func runDay(d *aoc.Day, parts []int, filename string, opts options) error {
+	if len(parts) == 0 {
+		for i := range d.Parts {
+			parts = append(parts, i+1)
+		}
+	}
+
+	if filename == "" {
+		filename = d.Path(opts.root, d.Input)
+	}
+	open := func() (io.ReadCloser, error) {
+		return os.Open(filename)
+	}
+
+	answers, err := opts.answers.forYear(d.Year)
+	if err != nil {
+		return err
+	}
+	info, err := inspectInput(d, opts.root, open, answers, parts)
+	if err != nil {
+		return err
+	}
+	info.print()
+
+	// answers are only known for the puzzle as it's set, so with params changed there's nothing
+	// to check them against, and they mustn't be recorded as if they were the real answers
+	overridden := d.Overridden(opts.params)
+	if overridden {
+		fmt.Println("Params are overridden, so answers aren't checked or recorded")
+	}
+
+	for _, part := range parts {
+		file, err := open()
+		if err != nil {
+			return err
+		}
+
+		r, _, err := solve(d, part, file, opts.params, opts.maxMemory)
+		file.Close()
+		if err != nil {
+			return err
+		}
+		if !overridden {
+			r.check(answers, info.Fingerprint)
+		}
+		r.print()
+
+		if opts.record && !overridden && r.Answer != "" {
+			answers.record(info.Fingerprint, d.Number, info.Kind == inputExample, part, r.Answer)
+		}
+	}
+
+	if opts.record {
+		return answers.save()
+	}
+	return nil
+}
+
+// runMode runs one of a day's modes against the input.  a mode isn't part of the puzzle, so
+// there's no answer to check or record, and the input isn't looked up
+func runMode(d *aoc.Day, name string, filename string, opts options) error {
+	m, ok := d.Mode(name)
+	if !ok {
+		return fmt.Errorf("%s has no mode %s", d, name)
+	}
+	if err := d.SetParams(opts.params); err != nil {
+		return err
+	}
+
+	if filename == "" {
+		filename = d.Path(opts.root, d.Input)
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	r, _, err := watched(d, opts.maxMemory, func() (any, error) {
+		return m.Run(file), nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", d, name, err)
+	}
+	r.Mode = name
+	r.print()
+	return nil
+}
+
+func listDays(years []int) {
+	for _, year := range years {
+		fmt.Printf("%d (%s)\n", year, aoc.YearDir(year))
+		listYear(year)
+	}
+}
+
+func listYear(year int) {
+	for _, d := range aoc.YearDays(year) {
+		fmt.Printf("%2d  %-26s parts=%d  input=%s", d.Number, d.Title, len(d.Parts), d.Input)
+		if d.Capabilities != 0 {
+			fmt.Printf("  [%s]", d.Capabilities)
+		}
+		fmt.Println()
+		for _, m := range d.Modes {
+			fmt.Printf("      %s\t%s\n", m.Name, m.Usage)
+		}
+		for _, p := range d.Params {
+			fmt.Printf("      -p %s=%s\t%s\n", p.Name, p.Default, p.Usage)
+		}
+	}
+}
+
+// parseDay reads a day given as a number or as year/day.  a number on its own is in the given year,
+// or the latest year if that's 0
+func parseDay(s string, year int) (*aoc.Day, error) {
+	if y, d, ok := strings.Cut(s, "/"); ok {
+		var err error
+		if year, err = strconv.Atoi(y); err != nil {
+			return nil, fmt.Errorf("invalid year %q", y)
+		}
+		s = d
+	}
+	day, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid day %q", s)
+	}
+
+	if year == 0 {
+		year = latestYear()
+	}
+	d, ok := aoc.Lookup(year, day)
+	if !ok {
+		return nil, fmt.Errorf("%d day %d is not registered", year, day)
+	}
+	return d, nil
+}
+
+// generateCommand handles `runner generate <day> args...`, writing the generated input to stdout
+func generateCommand(year int, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: runner generate [<year>/]<day> [args...]")
+	}
+	d, err := parseDay(args[0], year)
+	if err != nil {
+		return err
+	}
+	if d.Generate == nil {
+		return fmt.Errorf("%s can't generate inputs", d)
+	}
+	return d.Generate(os.Stdout, args[1:])
+}
+
+// latestYear is the year a day number on its own refers to
+func latestYear() int {
+	years := aoc.Years()
+	if len(years) == 0 {
+		return 0
+	}
+	return years[len(years)-1]
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: runner [flags] list")
+	fmt.Fprintln(os.Stderr, "       runner [flags] all")
+	fmt.Fprintln(os.Stderr, "       runner [flags] [<year>/]<day> [part1|part2] [input.txt]")
+	fmt.Fprintln(os.Stderr, "       runner [flags] [<year>/]<day> <mode> [input.txt]")
+	fmt.Fprintln(os.Stderr, "       runner [flags] new <year> <day>")
+	fmt.Fprintln(os.Stderr, "       runner [flags] generate [<year>/]<day> [args...]")
+	flag.PrintDefaults()
+}
+
+func main() {
+	root := flag.String("root", ".", "repo root that default input paths are relative to")
+	yearFlag := flag.Int("year", 0, "year of the days to run or list, defaults to the latest registered year for a single day and every year otherwise")
+	addr := flag.String("serve", "", "serve the registered days over http on this address instead of running one")
+	params := paramFlags{}
+	flag.Var(params, "p", "override a puzzle param as name=value, can be repeated")
+	var maxMemory memoryFlag
+	flag.Var(&maxMemory, "max-memory", "abort a solve once its heap grows past this size, like 512M or 2G")
+	answersName := flag.String("answers", "answers.json", "file in each year's directory holding the expected answers, keyed by input fingerprint")
+	record := flag.Bool("record", false, "save this run's answers as the expected answers for its input")
+	flag.Usage = usage
+	flag.Parse()
+
+	stores := newAnswerStores(*root, *answersName)
+	opts := options{root: *root, params: params, maxMemory: uint64(maxMemory), answers: stores, record: *record}
+
+	if *addr != "" {
+		if err := serve(*addr, opts); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	args := flag.Args()
+	if len(args) > 0 && args[0] == "new" {
+		if err := scaffoldCommand(*root, args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(args) > 0 && args[0] == "generate" {
+		if err := generateCommand(*yearFlag, args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	years := aoc.Years()
+	if *yearFlag != 0 {
+		years = []int{*yearFlag}
+	}
+
+	year := *yearFlag
+	day := 0
+	parts := []int{}
+	filename := ""
+	mode := ""
+	all := false
+	for _, v := range args {
+		if v == "list" {
+			listDays(years)
+			return
+		}
+		if v == "all" {
+			all = true
+			continue
+		}
+		if strings.HasSuffix(v, ".txt") {
+			filename = v
+			continue
+		}
+
+		// the first number is the day, optionally as year/day, and anything after that picks a part
+		if y, d, ok := strings.Cut(v, "/"); ok && day == 0 {
+			var err error
+			if year, err = strconv.Atoi(y); err != nil {
+				usage()
+				os.Exit(2)
+			}
+			v = d
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(v, "part"))
+		if err != nil && day != 0 && mode == "" && !strings.HasPrefix(v, "part") {
+			// anything after the day that isn't a part is one of the day's modes
+			mode = v
+			continue
+		}
+		if err != nil {
+			usage()
+			os.Exit(2)
+		}
+		if day == 0 && !strings.HasPrefix(v, "part") {
+			day = n
+		} else {
+			parts = append(parts, n)
+		}
+	}
+
+	if year == 0 {
+		year = latestYear()
+	}
+
+	var err error
+	if all {
+		// params are per day, so they don't make sense across every day
+		opts.params = nil
+		for _, y := range years {
+			for _, d := range aoc.YearDays(y) {
+				if err = runDay(d, nil, "", opts); err != nil {
+					break
+				}
+			}
+			if err != nil {
+				break
+			}
+		}
+	} else if day == 0 {
+		usage()
+		os.Exit(2)
+	} else if d, ok := aoc.Lookup(year, day); !ok {
+		err = fmt.Errorf("%d day %d is not registered", year, day)
+	} else if mode != "" {
+		err = runMode(d, mode, filename, opts)
+	} else {
+		err = runDay(d, parts, filename, opts)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type memoryStats struct {
+	PeakHeap   uint64 `json:"peak_heap"`
+	TotalAlloc uint64 `json:"total_alloc"`
+	NumGC      uint32 `json:"gc_count"`
+}
+
+// how often the heap is sampled while a solve runs.  ReadMemStats stops the world,
+// so this is a tradeoff between catching short spikes and slowing the solve down
+const memorySampleInterval = 5 * time.Millisecond
+
+type memoryBudgetError struct {
+	budget uint64
+	used   uint64
+}
+
+func (e memoryBudgetError) Error() string {
+	return fmt.Sprintf("aborted after heap reached %s, over the %s budget", formatBytes(e.used), formatBytes(e.budget))
+}
+
+// watchSolve runs fn on its own goroutine and samples the heap until it returns.  if the heap
+// grows past maxMemory (0 means no limit), it gives up on fn and returns a memoryBudgetError
+// right away.  go can't kill a goroutine, so an abandoned fn keeps running in the background,
+// holding on to everything it allocated, and done is closed once it really finishes.  the cli
+// exits after an abort, which is the only way to get that memory back; the server turns new
+// solves away until done is closed
+func watchSolve(maxMemory uint64, fn func() error) (stats memoryStats, done <-chan struct{}, err error) {
+	// start from a collected heap so garbage from the previous solve isn't counted against this one
+	runtime.GC()
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	finished := make(chan struct{})
+	var fnErr error
+	go func() {
+		defer close(finished)
+		defer func() {
+			if r := recover(); r != nil {
+				fnErr = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		fnErr = fn()
+	}()
+
+	ticker := time.NewTicker(memorySampleInterval)
+	defer ticker.Stop()
+
+	var current runtime.MemStats
+	peak := before.HeapAlloc
+	sample := func() {
+		runtime.ReadMemStats(&current)
+		if current.HeapAlloc > peak {
+			peak = current.HeapAlloc
+		}
+		stats = memoryStats{
+			PeakHeap:   peak,
+			TotalAlloc: current.TotalAlloc - before.TotalAlloc,
+			NumGC:      current.NumGC - before.NumGC,
+		}
+	}
+
+	for {
+		select {
+		case <-finished:
+			sample()
+			return stats, finished, fnErr
+		case <-ticker.C:
+			sample()
+			if maxMemory > 0 && peak > maxMemory {
+				return stats, finished, memoryBudgetError{budget: maxMemory, used: peak}
+			}
+		}
+	}
+}
+
+var sizeSuffixes = []string{"B", "KiB", "MiB", "GiB", "TiB"}
+
+func formatBytes(n uint64) string {
+	size := float64(n)
+	i := 0
+	for size >= 1024 && i < len(sizeSuffixes)-1 {
+		size /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d B", n)
+	}
+	return fmt.Sprintf("%.1f %s", size, sizeSuffixes[i])
+}
+
+// parseBytes reads sizes like 512M, 2G or 1048576.  the suffixes are powers of 1024
+func parseBytes(size string) (uint64, error) {
+	s := strings.ToUpper(strings.TrimSpace(size))
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "B"), "I")
+
+	multiplier := uint64(1)
+	for i, suffix := range []string{"K", "M", "G", "T"} {
+		if strings.HasSuffix(s, suffix) {
+			multiplier = 1 << (10 * (i + 1))
+			s = strings.TrimSuffix(s, suffix)
+			break
+		}
+	}
+
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+	return n * multiplier, nil
+}
+
+// memoryFlag lets -max-memory take human sizes
+type memoryFlag uint64
+
+func (m *memoryFlag) String() string {
+	if *m == 0 {
+		return "0"
+	}
+	return formatBytes(uint64(*m))
+}
+
+func (m *memoryFlag) Set(s string) error {
+	n, err := parseBytes(s)
+	if err != nil {
+		return err
+	}
+	*m = memoryFlag(n)
+	return nil
+}
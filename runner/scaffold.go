@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"citro.net/advent-2022-go/aoc"
+)
+
+const modulePrefix = "citro.net/advent-2022-go"
+
+// scaffoldCommand handles `runner new <year> <day>`
+func scaffoldCommand(root string, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: runner new <year> <day>")
+	}
+	year, err := strconv.Atoi(args[0])
+	if err != nil || year < 2015 {
+		return fmt.Errorf("invalid year %q", args[0])
+	}
+	day, err := strconv.Atoi(args[1])
+	if err != nil || day < 1 || day > 25 {
+		return fmt.Errorf("invalid day %q", args[1])
+	}
+
+	return scaffold(root, year, day)
+}
+
+// scaffold copies template/ into a new day in the year's directory, adds it to go.work and imports it
+// from the runner, so it registers itself as soon as its Parts are filled in
+func scaffold(root string, year int, day int) error {
+	if _, ok := aoc.Lookup(year, day); ok {
+		return fmt.Errorf("%d day %d is already registered", year, day)
+	}
+
+	name := fmt.Sprintf("day%02d", day)
+	rel := filepath.Join(aoc.YearDir(year), name)
+	dir := filepath.Join(root, rel)
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("%s already exists", dir)
+	}
+	module := path.Join(modulePrefix, filepath.ToSlash(rel))
+
+	replacer := strings.NewReplacer(
+		modulePrefix+"/dayXX", module,
+		"dayXX", name,
+	)
+	placeholders := map[string]int{"Year": year, "Number": day}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(filepath.Join(root, "template"))
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		// only the template's sources and inputs are copied, not anything built from it
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".go" && ext != ".mod" && ext != ".txt") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(root, "template", e.Name()))
+		if err != nil {
+			return err
+		}
+		data = []byte(replacer.Replace(string(data)))
+		if ext == ".go" {
+			for field, value := range placeholders {
+				pattern := regexp.MustCompile(field + `:(\s*)0, // @todo`)
+				data = pattern.ReplaceAll(data, []byte(fmt.Sprintf("%s:${1}%d,", field, value)))
+			}
+			if data, err = format.Source(data); err != nil {
+				return err
+			}
+		}
+
+		target := filepath.Join(dir, strings.ReplaceAll(e.Name(), "dayXX", name))
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return err
+		}
+	}
+
+	if err := addToWorkspace(filepath.Join(root, "go.work"), "./"+filepath.ToSlash(rel)); err != nil {
+		return err
+	}
+	if err := addRunnerImport(filepath.Join(root, "runner", fmt.Sprintf("days%d.go", year)), year, module); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %s for %d day %d as %s\n", dir, year, day, module)
+	return nil
+}
+
+// insertSorted adds line to the block of lines that starts after the first line equal to open and
+// ends at the next line equal to ")", keeping the block in order
+func insertSorted(lines []string, open string, line string) ([]string, error) {
+	start := -1
+	for i, l := range lines {
+		if start == -1 && l == open {
+			start = i + 1
+		} else if start != -1 && l == ")" {
+			block := append(append([]string{}, lines[start:i]...), line)
+			sort.Strings(block)
+			return append(append(append([]string{}, lines[:start]...), block...), lines[i:]...), nil
+		}
+	}
+	return nil, fmt.Errorf("no %q block found", open)
+}
+
+func addToWorkspace(workFile string, dir string) error {
+	data, err := os.ReadFile(workFile)
+	if err != nil {
+		return err
+	}
+
+	lines, err := insertSorted(strings.Split(string(data), "\n"), "use (", "\t"+dir)
+	if err != nil {
+		return fmt.Errorf("%s: %w", workFile, err)
+	}
+	return os.WriteFile(workFile, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// addRunnerImport imports the new day from the runner's file for that year, starting the file if
+// this is the year's first day
+func addRunnerImport(importFile string, year int, module string) error {
+	data, err := os.ReadFile(importFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		data = []byte(fmt.Sprintf("package main\n\n// every %d day registers itself with aoc at init time, so importing the package is all it takes\nimport (\n)\n", year))
+	} else if err != nil {
+		return err
+	}
+
+	lines, err := insertSorted(strings.Split(string(data), "\n"), "import (", fmt.Sprintf("\t_ %q", module))
+	if err != nil {
+		return fmt.Errorf("%s: %w", importFile, err)
+	}
+	return os.WriteFile(importFile, []byte(strings.Join(lines, "\n")), 0644)
+}
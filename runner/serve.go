@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"citro.net/advent-2022-go/aoc"
+)
+
+type dayInfo struct {
+	Year         int               `json:"year"`
+	Number       int               `json:"number"`
+	Title        string            `json:"title"`
+	Parts        int               `json:"parts"`
+	Input        string            `json:"input"`
+	Capabilities []string          `json:"capabilities"`
+	Params       map[string]string `json:"params"`
+}
+
+type solveResponse struct {
+	result
+	Input inputInfo `json:"input"`
+}
+
+// most days keep their puzzle in package level vars, so only one solve can run at a time
+var solveLock sync.Mutex
+
+// abandoned is set while a solve that went over the memory budget is still running.  go can't
+// stop it, so the heap it's holding isn't reclaimed until it finishes by itself, and queueing
+// more solves behind it would only pile more memory on top
+var abandoned atomic.Bool
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func handleList(w http.ResponseWriter, r *http.Request) {
+	days := []dayInfo{}
+	for _, d := range aoc.Days() {
+		info := dayInfo{Year: d.Year, Number: d.Number, Title: d.Title, Parts: len(d.Parts), Input: d.Input, Capabilities: []string{}, Params: map[string]string{}}
+		if d.Capabilities != 0 {
+			info.Capabilities = strings.Split(d.Capabilities.String(), ",")
+		}
+		// local params can't be set over http, so there's no point listing them
+		for _, p := range d.Params {
+			if !p.Local {
+				info.Params[p.Name] = p.Default
+			}
+		}
+		days = append(days, info)
+	}
+	writeJSON(w, http.StatusOK, days)
+}
+
+// handleSolve solves /days/{year}/{day}/{part}, or /days/{day}/{part} for the latest year.  the request
+// body is used as the puzzle input, falling back to the day's default input when it is empty, and
+// query params override puzzle params
+func handleSolve(opts options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		segments := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/days/"), "/"), "/")
+		if len(segments) == 2 {
+			segments = append([]string{strconv.Itoa(latestYear())}, segments...)
+		}
+		if len(segments) != 3 {
+			writeError(w, http.StatusNotFound, fmt.Errorf("expected /days/{year}/{day}/{part}"))
+			return
+		}
+
+		numbers := make([]int, len(segments))
+		for i, s := range segments {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+			numbers[i] = n
+		}
+		year, day, part := numbers[0], numbers[1], numbers[2]
+
+		d, ok := aoc.Lookup(year, day)
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Errorf("%d day %d is not registered", year, day))
+			return
+		}
+		answers, err := opts.answers.forYear(year)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		open := func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+		if len(body) == 0 {
+			open = func() (io.ReadCloser, error) {
+				return d.OpenInput(opts.root)
+			}
+		}
+
+		info, err := inspectInput(d, opts.root, open, answers, []int{part})
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		input, err := open()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		defer input.Close()
+
+		params := map[string]string{}
+		for name, values := range r.URL.Query() {
+			if p, ok := d.Param(name); ok && p.Local {
+				writeError(w, http.StatusForbidden, fmt.Errorf("param %s can only be set from the command line", name))
+				return
+			}
+			params[name] = values[len(values)-1]
+		}
+
+		if abandoned.Load() {
+			writeError(w, http.StatusServiceUnavailable, errors.New("an aborted solve is still running and holding its memory, try again once it finishes"))
+			return
+		}
+
+		solveLock.Lock()
+		res, done, err := solve(d, part, input, params, opts.maxMemory)
+
+		var budgetErr memoryBudgetError
+		if errors.As(err, &budgetErr) {
+			// the aborted solve is still running and still owns the day's package state,
+			// so hold the lock, and turn new solves away, until it really finishes
+			abandoned.Store(true)
+			go func() {
+				<-done
+				abandoned.Store(false)
+				solveLock.Unlock()
+			}()
+			writeError(w, http.StatusInsufficientStorage, err)
+			return
+		}
+		solveLock.Unlock()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if !d.Overridden(params) {
+			res.check(answers, info.Fingerprint)
+		}
+		writeJSON(w, http.StatusOK, solveResponse{res, info})
+	}
+}
+
+func serve(addr string, opts options) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/days", handleList)
+	mux.HandleFunc("/days/", handleSolve(opts))
+
+	fmt.Printf("Serving %d days on %s\n", len(aoc.Days()), addr)
+	return http.ListenAndServe(addr, mux)
+}
@@ -1,14 +1,15 @@
-package main
+package dayXX
 
 import (
 	"bufio"
-	"os"
-	"strings"
+	"io"
+
+	"citro.net/advent-2022-go/aoc"
 )
 
 // var puzzle ...
 
-func loadPuzzle(file *os.File) {
+func loadPuzzle(file io.Reader) {
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -20,26 +21,23 @@ func loadPuzzle(file *os.File) {
 	}
 }
 
-func part1() {
+func part1(file io.Reader) any {
+	loadPuzzle(file)
+	return nil
 }
 
-func part2() {
+func part2(file io.Reader) any {
+	loadPuzzle(file)
+	return nil
 }
 
-func main() {
-	filename := "input.txt"
-	method := part1
-	for _, v := range os.Args {
-		if v == "part2" || v == "2" {
-			method = part2
-		}
-		if strings.HasSuffix(v, ".txt") {
-			filename = v
-		}
-	}
-
-	file, _ := os.Open(filename)
-	defer file.Close()
-	loadPuzzle(file)
-	method()
+func init() {
+	aoc.Register(aoc.Day{
+		Year:     0, // @todo
+		Number:   0, // @todo
+		Title:    "",
+		Input:    "dayXX/input.txt",
+		Examples: []string{"dayXX/intro.txt"},
+		Parts:    []aoc.Solver{part1, part2},
+	})
 }